@@ -24,7 +24,7 @@ func main() {
 	}
 
 	// move the window to index 10
-	err = window.Move(session.Name, 10)
+	err = window.Move(session.Target(), 10)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,324 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Tmux paste buffer object.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#BUFFERS
+type Buffer struct {
+	Created time.Time
+	Name    string
+	Sample  string
+	Size    int
+
+	tmux *Tmux
+}
+
+// Lists all paste buffers, most recent first.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#list-buffers
+func (t *Tmux) ListBuffers() ([]*Buffer, error) {
+	o, err := t.query().
+		cmd("list-buffers").
+		bufferVars().
+		run()
+	if err != nil {
+		return nil, errors.New("failed to list buffers")
+	}
+
+	out := make([]*Buffer, 0)
+	for _, item := range o.collect() {
+		b, err := item.toBuffer(t)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+
+	return out, nil
+}
+
+// Gets a buffer by name.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#buffer_name
+func (t *Tmux) GetBufferByName(name string) (*Buffer, error) {
+	buffers, err := t.ListBuffers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range buffers {
+		if b.Name == name {
+			return b, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Set buffer options.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-buffer
+type SetBufferOptions struct {
+	// Name of the buffer. If empty, a new automatically named buffer is created.
+	Name string
+
+	// Appends the data to the buffer instead of replacing it.
+	Append bool
+
+	// Renames the buffer.
+	NewName string
+
+	// Forwards the buffer to the clipboard of the clients using OSC 52.
+	Clipboard bool
+
+	// Client whose clipboard is set. Defaults to all clients.
+	TargetClient Target
+}
+
+// Sets the content of a buffer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-buffer
+func (t *Tmux) SetBuffer(data string, op *SetBufferOptions) error {
+	q := t.query().
+		cmd("set-buffer")
+
+	if op != nil {
+		if op.Name != "" {
+			q.fargs("-b", op.Name)
+		}
+
+		if op.Append {
+			q.fargs("-a")
+		}
+
+		if op.NewName != "" {
+			q.fargs("-n", op.NewName)
+		}
+
+		if op.Clipboard {
+			q.fargs("-w")
+		}
+
+		if op.TargetClient != "" {
+			q.fargs("-t", string(op.TargetClient))
+		}
+	}
+
+	_, err := q.pargs("--", data).run()
+	if err != nil {
+		return errors.New("failed to set buffer")
+	}
+
+	return nil
+}
+
+// Returns the content of a buffer.
+// An empty name refers to the most recent buffer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-buffer
+func (t *Tmux) ShowBuffer(name string) (string, error) {
+	q := t.query().
+		cmd("show-buffer")
+
+	if name != "" {
+		q.fargs("-b", name)
+	}
+
+	o, err := q.run()
+	if err != nil {
+		return "", errors.New("failed to show buffer")
+	}
+
+	return o.result, nil
+}
+
+// Loads the content of a buffer from a reader.
+// An empty name creates a new automatically named buffer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#load-buffer
+func (t *Tmux) LoadBuffer(name string, r io.Reader) error {
+	q := t.query().
+		cmd("load-buffer")
+
+	if name != "" {
+		q.fargs("-b", name)
+	}
+
+	q.pargs("-")
+	q.pipeIn(r)
+
+	_, err := q.run()
+	if err != nil {
+		return errors.New("failed to load buffer")
+	}
+
+	return nil
+}
+
+// Saves the content of a buffer to a writer, streaming it without holding it in memory.
+// An empty name refers to the most recent buffer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#save-buffer
+func (t *Tmux) SaveBuffer(name string, w io.Writer) error {
+	q := t.query().
+		cmd("save-buffer")
+
+	if name != "" {
+		q.fargs("-b", name)
+	}
+
+	err := q.pargs("-").stream(w)
+	if err != nil {
+		return errors.New("failed to save buffer")
+	}
+
+	return nil
+}
+
+// Deletes a buffer.
+// An empty name refers to the most recent buffer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#delete-buffer
+func (t *Tmux) DeleteBuffer(name string) error {
+	q := t.query().
+		cmd("delete-buffer")
+
+	if name != "" {
+		q.fargs("-b", name)
+	}
+
+	_, err := q.run()
+	if err != nil {
+		return errors.New("failed to delete buffer")
+	}
+
+	return nil
+}
+
+// Returns the full content of this buffer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-buffer
+func (b *Buffer) Content() (string, error) {
+	return b.tmux.ShowBuffer(b.Name)
+}
+
+// Saves the content of this buffer to a writer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#save-buffer
+func (b *Buffer) Save(w io.Writer) error {
+	return b.tmux.SaveBuffer(b.Name, w)
+}
+
+// Deletes this buffer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#delete-buffer
+func (b *Buffer) Delete() error {
+	return b.tmux.DeleteBuffer(b.Name)
+}
+
+// Paste buffer options.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#paste-buffer
+type PasteOptions struct {
+	// Uses bracketed paste if the application in the pane requested it.
+	Bracketed bool
+
+	// Replaces line feeds with this separator instead of carriage returns.
+	Separator string
+
+	// Pastes line feeds as is, without replacement.
+	NoReplace bool
+
+	// Deletes the buffer after pasting.
+	Delete bool
+}
+
+// Pastes a buffer into the pane.
+// An empty name refers to the most recent buffer.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#paste-buffer
+func (p *Pane) PasteBuffer(name string, op *PasteOptions) error {
+	q := p.tmux.query().
+		cmd("paste-buffer").
+		fargs("-t", p.Id)
+
+	if name != "" {
+		q.fargs("-b", name)
+	}
+
+	if op != nil {
+		if op.Bracketed {
+			q.fargs("-p")
+		}
+
+		if op.Separator != "" {
+			q.fargs("-s", op.Separator)
+		}
+
+		if op.NoReplace {
+			q.fargs("-r")
+		}
+
+		if op.Delete {
+			q.fargs("-d")
+		}
+	}
+
+	_, err := q.run()
+	if err != nil {
+		return errors.New("failed to paste buffer")
+	}
+
+	return nil
+}
+
+// Pastes the most recent buffer into the pane.
+// Shorthand for 'PasteBuffer' but with default options.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#paste-buffer
+func (p *Pane) Paste() error {
+	return p.PasteBuffer("", nil)
+}
+
+// Sets the buffer variables in the query.
+// The sample is last since it may contain the separator.
+func (q *query) bufferVars() *query {
+	return q.vars(
+		varBufferCreated,
+		varBufferName,
+		varBufferSize,
+		varBufferSample,
+	)
+}
+
+// Converts a QueryResult to a Buffer.
+func (q queryResult) toBuffer(t *Tmux) (*Buffer, error) {
+	created, err := parseTime(q.get(varBufferCreated))
+	if err != nil {
+		return nil, err
+	}
+	name := q.get(varBufferName)
+	sample := q.get(varBufferSample)
+	size, _ := strconv.Atoi(q.get(varBufferSize))
+
+	b := &Buffer{
+		Created: created,
+		Name:    name,
+		Sample:  sample,
+		Size:    size,
+
+		tmux: t,
+	}
+
+	return b, nil
+}
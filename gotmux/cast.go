@@ -0,0 +1,435 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Command to run in a pane used to replay a cast.
+// It writes the input of the pane as is to its terminal.
+const CastPlayerCommand = "stty raw -echo; exec cat"
+
+// Header of an asciicast v2 file.
+//
+// Reference: https://docs.asciinema.org/manual/asciicast/v2/
+type CastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event of an asciicast v2 file.
+//
+// Reference: https://docs.asciinema.org/manual/asciicast/v2/
+type CastEvent struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// Cast event types.
+const (
+	CastEventOutput = "o"
+	CastEventInput  = "i"
+	CastEventResize = "r"
+	CastEventMarker = "m"
+)
+
+// Record options.
+type RecordOptions struct {
+	// Title of the recording.
+	Title string
+
+	// Records the current content of the pane as the first event.
+	CaptureScreen bool
+
+	// Interval at which the pane size is checked for resize events.
+	// Defaults to 500ms.
+	ResizeInterval time.Duration
+}
+
+// Records the output of a pane to an asciicast v2 stream.
+//
+// Reference: https://docs.asciinema.org/manual/asciicast/v2/
+type Recorder struct {
+	pane  *Pane
+	pipe  *Pipe
+	start time.Time
+
+	mu      sync.Mutex
+	w       io.Writer
+	partial []byte
+	err     error
+
+	width, height int
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// Starts recording the output of the pane to the writer in asciicast v2 format.
+// Replaces any existing pipe on the pane.
+//
+// Reference: https://docs.asciinema.org/manual/asciicast/v2/
+func (p *Pane) Record(w io.Writer, op *RecordOptions) (*Recorder, error) {
+	o := RecordOptions{}
+	if op != nil {
+		o = *op
+	}
+
+	if o.ResizeInterval == 0 {
+		o.ResizeInterval = 500 * time.Millisecond
+	}
+
+	// The screen is captured with the size, so that the first frame matches the header.
+	var initial *paneCapture
+	var width, height int
+	if o.CaptureScreen {
+		c, err := p.captureScreen()
+		if err != nil {
+			return nil, err
+		}
+		initial, width, height = c, c.width, c.height
+	} else {
+		var err error
+		width, height, err = p.size()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Recorder{
+		pane:   p,
+		w:      w,
+		start:  time.Now(),
+		width:  width,
+		height: height,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	header := CastHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Title:     o.Title,
+		Env: map[string]string{
+			"TERM":  "tmux-256color",
+			"SHELL": os.Getenv("SHELL"),
+		},
+	}
+	b, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Fprintf(w, "%s\n", b); err != nil {
+		return nil, errors.New("failed to write cast header")
+	}
+
+	if initial != nil {
+		content := toCRLF(strings.TrimSuffix(initial.content, "\n"))
+		cursor := fmt.Sprintf("\x1b[%d;%dH", initial.cursor.Y+1, initial.cursor.X+1)
+		r.event(CastEventOutput, "\x1b[2J\x1b[H"+content+cursor)
+	}
+
+	pipe, err := p.PipeTo(recorderWriter{r})
+	if err != nil {
+		return nil, err
+	}
+	r.pipe = pipe
+
+	go r.watchSize(o.ResizeInterval)
+
+	return r, nil
+}
+
+// Writer receiving the pane output.
+type recorderWriter struct {
+	r *Recorder
+}
+
+// Writes the output as an event, holding back incomplete UTF-8 sequences.
+func (w recorderWriter) Write(b []byte) (int, error) {
+	r := w.r
+	r.mu.Lock()
+	data := append(r.partial, b...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	r.partial = append([]byte{}, data[cut:]...)
+	r.mu.Unlock()
+
+	if cut > 0 {
+		r.event(CastEventOutput, string(data[:cut]))
+	}
+
+	return len(b), nil
+}
+
+// Writes an event with the elapsed time.
+func (r *Recorder) event(kind, data string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+
+	elapsed := time.Since(r.start).Seconds()
+	b, err := json.Marshal([]any{json.Number(strconv.FormatFloat(elapsed, 'f', 6, 64)), kind, data})
+	if err == nil {
+		_, err = fmt.Fprintf(r.w, "%s\n", b)
+	}
+	if err != nil {
+		r.err = errors.New("failed to write cast event")
+	}
+}
+
+// Polls the pane size and records resize events.
+func (r *Recorder) watchSize(interval time.Duration) {
+	defer close(r.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-r.pipe.Done():
+			return
+		case <-ticker.C:
+			width, height, err := r.pane.size()
+			if err != nil {
+				continue
+			}
+			if width != r.width || height != r.height {
+				r.width, r.height = width, height
+				r.event(CastEventResize, fmt.Sprintf("%dx%d", width, height))
+			}
+		}
+	}
+}
+
+// Returns a channel that is closed when the recording ends,
+// either because it was stopped or because the pane died.
+func (r *Recorder) Done() <-chan struct{} {
+	return r.pipe.Done()
+}
+
+// Adds a marker event to the recording.
+func (r *Recorder) Mark(label string) {
+	r.event(CastEventMarker, label)
+}
+
+// Stops the recording.
+// Returns the first error that occurred while recording, if any.
+func (r *Recorder) Stop() error {
+	err := r.pipe.Close()
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	return err
+}
+
+// Reads an asciicast v2 stream.
+//
+// Reference: https://docs.asciinema.org/manual/asciicast/v2/
+func ReadCast(r io.Reader) (*CastHeader, []CastEvent, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		return nil, nil, errors.New("missing cast header")
+	}
+
+	header := &CastHeader{}
+	if err := json.Unmarshal(scanner.Bytes(), header); err != nil {
+		return nil, nil, errors.New("invalid cast header")
+	}
+
+	if header.Version != 2 {
+		return nil, nil, errors.New("unsupported cast version")
+	}
+
+	events := make([]CastEvent, 0)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var raw []any
+		if err := json.Unmarshal(line, &raw); err != nil || len(raw) != 3 {
+			return nil, nil, errors.New("invalid cast event")
+		}
+
+		t, okT := raw[0].(float64)
+		kind, okK := raw[1].(string)
+		data, okD := raw[2].(string)
+		if !okT || !okK || !okD {
+			return nil, nil, errors.New("invalid cast event")
+		}
+
+		events = append(events, CastEvent{Time: t, Type: kind, Data: data})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return header, events, nil
+}
+
+// Play options.
+type PlayOptions struct {
+	// Playback speed multiplier. Defaults to 1.
+	Speed float64
+
+	// Maximum pause between events. Zero keeps the original timing.
+	MaxIdle time.Duration
+
+	// Resizes the pane to the size of the recording and applies resize events.
+	Resize bool
+}
+
+// Replays an asciicast v2 stream into the pane.
+// The pane should run 'CastPlayerCommand' so that the output is written to its terminal.
+// Blocks until the playback is complete.
+//
+// Reference: https://docs.asciinema.org/manual/asciicast/v2/
+func (p *Pane) Play(r io.Reader, op *PlayOptions) error {
+	o := PlayOptions{}
+	if op != nil {
+		o = *op
+	}
+
+	if o.Speed <= 0 {
+		o.Speed = 1
+	}
+
+	header, events, err := ReadCast(r)
+	if err != nil {
+		return err
+	}
+
+	if o.Resize {
+		if err := p.resize(header.Width, header.Height); err != nil {
+			return err
+		}
+	}
+
+	pr, pw := io.Pipe()
+	pipe, err := p.PipeFrom(pr)
+	if err != nil {
+		return err
+	}
+
+	last := 0.0
+	for _, e := range events {
+		delay := time.Duration((e.Time - last) / o.Speed * float64(time.Second))
+		if o.MaxIdle > 0 && delay > o.MaxIdle {
+			delay = o.MaxIdle
+		}
+		last = e.Time
+
+		select {
+		case <-time.After(delay):
+		case <-pipe.Done():
+			pw.Close()
+			return pipe.Wait()
+		}
+
+		switch e.Type {
+		case CastEventOutput:
+			if _, err := io.WriteString(pw, e.Data); err != nil {
+				pw.Close()
+				return errors.New("failed to play cast")
+			}
+		case CastEventResize:
+			if !o.Resize {
+				continue
+			}
+			var width, height int
+			if _, err := fmt.Sscanf(e.Data, "%dx%d", &width, &height); err == nil {
+				_ = p.resize(width, height)
+			}
+		}
+	}
+
+	pw.Close()
+	return pipe.Wait()
+}
+
+// Returns the current size of the pane.
+func (p *Pane) size() (int, int, error) {
+	o, err := p.tmux.query().
+		cmd("display-message").
+		fargs("-t", p.Id).
+		vars(varPaneWidth, varPaneHeight).
+		run()
+	if err != nil {
+		return 0, 0, errors.New("failed to get pane size")
+	}
+
+	r := o.one()
+	width, _ := strconv.Atoi(r.get(varPaneWidth))
+	height, _ := strconv.Atoi(r.get(varPaneHeight))
+	return width, height, nil
+}
+
+// Resizes the pane.
+func (p *Pane) resize(width, height int) error {
+	_, err := p.tmux.query().
+		cmd("resize-pane").
+		fargs("-t", p.Id).
+		fargs("-x", strconv.Itoa(width), "-y", strconv.Itoa(height)).
+		run()
+	if err != nil {
+		return errors.New("failed to resize pane")
+	}
+
+	return nil
+}
+
+// Converts line feeds to carriage return and line feeds, as written by a terminal.
+func toCRLF(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			out = append(out, '\r')
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
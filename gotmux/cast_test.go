@@ -0,0 +1,119 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCast(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24,"timestamp":1700000000,"title":"demo","env":{"TERM":"tmux-256color"}}
+[0.5,"o","hello\r\n"]
+
+[1.25,"r","100x30"]
+[2,"m","mark"]
+[2.5,"o","\u001b[31mé"]
+`
+
+	header, events, err := ReadCast(strings.NewReader(cast))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantHeader := &CastHeader{
+		Version:   2,
+		Width:     80,
+		Height:    24,
+		Timestamp: 1700000000,
+		Title:     "demo",
+		Env:       map[string]string{"TERM": "tmux-256color"},
+	}
+	if !reflect.DeepEqual(header, wantHeader) {
+		t.Errorf("header = %+v, want %+v", header, wantHeader)
+	}
+
+	wantEvents := []CastEvent{
+		{Time: 0.5, Type: CastEventOutput, Data: "hello\r\n"},
+		{Time: 1.25, Type: CastEventResize, Data: "100x30"},
+		{Time: 2, Type: CastEventMarker, Data: "mark"},
+		{Time: 2.5, Type: CastEventOutput, Data: "\x1b[31mé"},
+	}
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("events = %+v, want %+v", events, wantEvents)
+	}
+}
+
+func TestReadCastInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cast string
+	}{
+		{"empty", ""},
+		{"invalid header", "not json\n"},
+		{"unsupported version", `{"version":1,"width":80,"height":24}` + "\n"},
+		{"invalid event", `{"version":2,"width":80,"height":24}` + "\n" + `[0.5,"o"]` + "\n"},
+		{"invalid event time", `{"version":2,"width":80,"height":24}` + "\n" + `["0.5","o","a"]` + "\n"},
+		{"invalid event data", `{"version":2,"width":80,"height":24}` + "\n" + `[0.5,"o",1]` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ReadCast(strings.NewReader(tt.cast)); err == nil {
+				t.Errorf("ReadCast(%q) succeeded, want error", tt.cast)
+			}
+		})
+	}
+}
+
+// Multi-byte runes split across writes are written whole in the next event.
+func TestRecorderWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []string
+	}{
+		{"ascii", []string{"ab", "cd"}, []string{"ab", "cd"}},
+		{"two bytes", []string{"a\xc3", "\xa9b"}, []string{"a", "éb"}},
+		{"three bytes", []string{"\xe4", "\xb8", "\x96x"}, []string{"世x"}},
+		{"four bytes", []string{"x\xf0\x9f", "\x98\x80"}, []string{"x", "😀"}},
+		{"whole runes", []string{"é", "世"}, []string{"é", "世"}},
+		{"escape then rune", []string{"\x1b[1m\xe4\xb8", "\x96"}, []string{"\x1b[1m", "世"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			r := &Recorder{w: &b, start: time.Now()}
+			for _, s := range tt.writes {
+				n, err := recorderWriter{r}.Write([]byte(s))
+				if err != nil || n != len(s) {
+					t.Fatalf("Write(%q) = %d, %v", s, n, err)
+				}
+			}
+
+			_, events, err := ReadCast(strings.NewReader(`{"version":2,"width":80,"height":24}` + "\n" + b.String()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0, len(events))
+			for _, e := range events {
+				if e.Type != CastEventOutput {
+					t.Errorf("event type = %q, want %q", e.Type, CastEventOutput)
+				}
+				got = append(got, e.Data)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+			if len(r.partial) != 0 {
+				t.Errorf("partial = %q, want none", r.partial)
+			}
+		})
+	}
+}
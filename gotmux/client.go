@@ -5,18 +5,20 @@
 package gotmux
 
 import (
+	"errors"
 	"strconv"
+	"time"
 )
 
 // Tmux client object.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
 type Client struct {
-	Activity     string
+	Activity     time.Time
 	CellHeight   int
 	CellWidth    int
 	ControlMode  bool
-	Created      string
+	Created      time.Time
 	Discarded    string
 	Flags        string
 	Height       int
@@ -40,6 +42,38 @@ type Client struct {
 	tmux *Tmux
 }
 
+// Re-reads the fields of the client from tmux, by client name.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#display-message
+func (c *Client) Refresh() error {
+	o, err := c.tmux.query().
+		cmd("display-message").
+		fargs("-c", c.Name).
+		clientVars().
+		run()
+	if err != nil {
+		return errors.New("failed to refresh client")
+	}
+
+	fresh, err := o.one().toClient(c.tmux)
+	if err != nil {
+		return err
+	}
+
+	// Targets that do not exist are not an error for display-message, but expand to empty values.
+	if fresh.Name != c.Name {
+		return errors.New("client not found")
+	}
+
+	*c = *fresh
+	return nil
+}
+
+// Returns the time since the last activity of the client.
+func (c *Client) IdleFor() time.Duration {
+	return time.Since(c.Activity)
+}
+
 // Gets the session that this client is attached to.
 func (c *Client) GetSession() (*Session, error) {
 	return c.tmux.GetSessionByName(c.Session)
@@ -76,12 +110,18 @@ func (q *query) clientVars() *query {
 }
 
 // Converts a QueryResult to a Client.
-func (q queryResult) toClient(t *Tmux) *Client {
-	activity := q.get(varClientActivity)
+func (q queryResult) toClient(t *Tmux) (*Client, error) {
+	activity, err := parseTime(q.get(varClientActivity))
+	if err != nil {
+		return nil, err
+	}
 	cellHeight, _ := strconv.Atoi(q.get(varClientCellHeight))
 	cellWidth, _ := strconv.Atoi(q.get(varClientCellWidth))
 	controlMode := isOne(q.get(varClientControlMode))
-	created := q.get(varClientCreated)
+	created, err := parseTime(q.get(varClientCreated))
+	if err != nil {
+		return nil, err
+	}
 	discarded := q.get(varClientDiscarded)
 	flags := q.get(varClientFlags)
 	height, _ := strconv.Atoi(q.get(varClientHeight))
@@ -131,5 +171,5 @@ func (q queryResult) toClient(t *Tmux) *Client {
 		tmux: t,
 	}
 
-	return c
+	return c, nil
 }
@@ -0,0 +1,207 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Copies the data to a new buffer and forwards it to the clipboard
+// of the attached clients using OSC 52.
+// Requires the set-clipboard option to be enabled.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-buffer
+func (t *Tmux) CopyToClipboard(data string) error {
+	return t.SetBuffer(data, &SetBufferOptions{
+		Clipboard: true,
+	})
+}
+
+// Returns true if the terminal of the client supports setting the clipboard.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#terminal-features
+func (c *Client) SupportsClipboard() bool {
+	return slices.Contains(parseList(c.Termfeatures), "clipboard")
+}
+
+// Asks the terminal of the client for its clipboard.
+// The content is stored in a new buffer once the terminal replies.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#refresh-client
+func (c *Client) RequestClipboard() error {
+	_, err := c.tmux.query().
+		cmd("refresh-client").
+		fargs("-l", "-t", c.Name).
+		run()
+	if err != nil {
+		return errors.New("failed to request clipboard")
+	}
+
+	return nil
+}
+
+// Watch buffers options.
+type WatchBuffersOptions struct {
+	// Session to attach the control mode client to.
+	// Defaults to the most recently used session.
+	Session string
+
+	// Interval at which buffers are polled on servers without
+	// the %paste-buffer-changed notification. Defaults to 500ms.
+	PollInterval time.Duration
+}
+
+// Watches paste buffers, calling a handler when a buffer is created or changed.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#CONTROL_MODE
+type BufferWatcher struct {
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	err      error
+}
+
+// Watches paste buffers, calling the handler when a buffer is created or changed.
+// Uses the control mode %paste-buffer-changed notification when the server supports it (tmux 3.4),
+// otherwise polls the buffers.
+// The control mode client counts as an attached client of the session and runs the client-attached hooks.
+// The watcher stops if the control client exits, for example when its session is destroyed,
+// or if the buffers cannot be listed. Done is then closed and Err returns the reason.
+// Feed the buffers to the host clipboard from the handler to mirror tmux copies.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#CONTROL_MODE
+func (t *Tmux) WatchBuffers(op *WatchBuffersOptions, handler func(b *Buffer)) (*BufferWatcher, error) {
+	o := WatchBuffersOptions{}
+	if op != nil {
+		o = *op
+	}
+
+	if o.PollInterval == 0 {
+		o.PollInterval = 500 * time.Millisecond
+	}
+
+	server, err := t.GetServerInformation()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &BufferWatcher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if server.VersionAtLeast("3.4") {
+		c, err := t.newControlClient(o.Session, func(n controlNotification) {
+			if n.Name != "paste-buffer-changed" || len(n.Args) == 0 {
+				return
+			}
+
+			b, err := t.GetBufferByName(n.Args[0])
+			if err == nil && b != nil {
+				handler(b)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		go func() {
+			defer close(w.done)
+			select {
+			case <-w.stop:
+				_ = c.Close()
+			case <-c.Done():
+				w.err = c.Err()
+			}
+		}()
+
+		return w, nil
+	}
+
+	seen, _, err := bufferStates(t)
+	if err != nil {
+		return nil, err
+	}
+
+	go w.poll(t, o.PollInterval, seen, handler)
+	return w, nil
+}
+
+// State of a buffer used to detect changes when polling.
+type bufferState struct {
+	created time.Time
+	size    int
+	sample  string
+}
+
+// Returns the current state of the buffers by name.
+func bufferStates(t *Tmux) (map[string]bufferState, []*Buffer, error) {
+	buffers, err := t.ListBuffers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(map[string]bufferState)
+	for _, b := range buffers {
+		out[b.Name] = bufferState{created: b.Created, size: b.Size, sample: b.Sample}
+	}
+
+	return out, buffers, nil
+}
+
+// Polls the buffers until stopped, calling the handler for new or changed buffers.
+func (w *BufferWatcher) poll(t *Tmux, interval time.Duration, seen map[string]bufferState, handler func(b *Buffer)) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			states, buffers, err := bufferStates(t)
+			if err != nil {
+				w.err = err
+				return
+			}
+
+			// Buffers are listed most recent first, report them in order of creation.
+			for i := len(buffers) - 1; i >= 0; i-- {
+				b := buffers[i]
+				if prev, ok := seen[b.Name]; !ok || prev != states[b.Name] {
+					handler(b)
+				}
+			}
+			seen = states
+		}
+	}
+}
+
+// Returns a channel that is closed when the watcher stops.
+func (w *BufferWatcher) Done() <-chan struct{} {
+	return w.done
+}
+
+// Returns the reason the watcher stopped on its own, or nil if it is running or was stopped.
+func (w *BufferWatcher) Err() error {
+	select {
+	case <-w.done:
+		return w.err
+	default:
+		return nil
+	}
+}
+
+// Stops watching the buffers.
+func (w *BufferWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
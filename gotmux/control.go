@@ -0,0 +1,144 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+)
+
+// Control mode client, used to receive notifications from the server.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#CONTROL_MODE
+type controlClient struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	done  chan struct{}
+
+	closed atomic.Bool
+	err    error
+}
+
+// Control mode notification such as '%session-changed $1 name'.
+type controlNotification struct {
+	Name string
+	Args []string
+}
+
+// Attaches a read-only control mode client to a session and calls the handler for every notification.
+// An empty session attaches to the most recently used session.
+// Returns once the client is attached, or the error of the attach.
+// The client counts as an attached client of the session and runs the client-attached hooks.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#CONTROL_MODE
+func (t *Tmux) newControlClient(session string, handler func(n controlNotification)) (*controlClient, error) {
+	q := t.query().
+		cmd("-C", "attach-session").
+		fargs("-f", "no-output,ignore-size,read-only")
+
+	if session != "" {
+		q.fargs("-t", session)
+	}
+
+	cmd := q.prepare()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.New("failed to start control client")
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.New("failed to start control client")
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, errors.New("failed to start control client")
+	}
+
+	c := &controlClient{
+		cmd:   cmd,
+		stdin: stdin,
+		done:  make(chan struct{}),
+	}
+
+	// The output of the attach command is the first block, ending with %error if it failed.
+	attached := make(chan error, 1)
+	go func() {
+		defer close(c.done)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+		// Skips the output of commands, which is wrapped in %begin and %end or %error.
+		inBlock, first := false, true
+		block := make([]string, 0)
+		exit := ""
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "%begin "):
+				inBlock = true
+				block = block[:0]
+			case strings.HasPrefix(line, "%end "), strings.HasPrefix(line, "%error "):
+				inBlock = false
+				if first {
+					first = false
+					if strings.HasPrefix(line, "%error ") {
+						attached <- errors.New("failed to attach control client: " + strings.Join(block, " "))
+					} else {
+						attached <- nil
+					}
+				}
+			case inBlock:
+				block = append(block, line)
+			case line == "%exit", strings.HasPrefix(line, "%exit "):
+				exit = strings.TrimPrefix(strings.TrimPrefix(line, "%exit"), " ")
+			case strings.HasPrefix(line, "%"):
+				fields := strings.Split(line[1:], " ")
+				handler(controlNotification{Name: fields[0], Args: fields[1:]})
+			}
+		}
+		_ = cmd.Wait()
+
+		if first {
+			attached <- errors.New("failed to attach control client")
+		} else if !c.closed.Load() {
+			if exit == "" {
+				exit = "exited"
+			}
+			c.err = errors.New("control client " + exit)
+		}
+	}()
+
+	if err := <-attached; err != nil {
+		stdin.Close()
+		<-c.done
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Returns a channel that is closed when the control client exits.
+func (c *controlClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Returns the reason the client exited without being closed, such as its session being destroyed.
+// Only valid once the client is done.
+func (c *controlClient) Err() error {
+	return c.err
+}
+
+// Detaches the control client and waits for it to exit.
+func (c *controlClient) Close() error {
+	c.closed.Store(true)
+	err := c.stdin.Close()
+	<-c.done
+	return err
+}
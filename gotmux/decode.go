@@ -0,0 +1,240 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Objects listed by a custom query.
+type QueryScope int
+
+// Enumeration of query scopes.
+const (
+	// The target itself, with display-message. An empty target is the current pane.
+	QueryScopeTarget QueryScope = iota
+
+	// Sessions of the server. The target is ignored.
+	QueryScopeSessions
+
+	// Windows of the target session, or of all sessions if the target is empty.
+	QueryScopeWindows
+
+	// Panes of the target window, or of all windows if the target is empty.
+	QueryScopePanes
+
+	// Panes of all windows of the target session.
+	QueryScopeSessionPanes
+
+	// Clients attached to the target session, or all clients if the target is empty.
+	QueryScopeClients
+)
+
+// A field of a struct decoded from a tmux variable.
+type decodeField struct {
+	index    int
+	variable string
+}
+
+// Fetches only the tmux variables named by the `tmux` tags of the fields of T, and decodes one T per object.
+// Returns an error if a value contains a newline or the query separator "-:-", which some names and titles may.
+// Supported field types are string, bool, integers, time.Time (from unix timestamps, zero when unset) and []string (from comma separated lists).
+//
+//	type PanePath struct {
+//		Id     string `tmux:"pane_id"`
+//		Path   string `tmux:"pane_current_path"`
+//		Active bool   `tmux:"pane_active"`
+//	}
+//
+//	panes, err := gotmux.Query[PanePath](t, "main:0", gotmux.QueryScopePanes)
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#FORMATS
+func Query[T any](t *Tmux, target Target, scope QueryScope) ([]T, error) {
+	fields, vars, err := queryFields[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	q := t.query()
+	switch scope {
+	case QueryScopeTarget:
+		q.cmd("display-message")
+		if target != "" {
+			q.fargs("-t", target.arg())
+		}
+	case QueryScopeSessions:
+		q.cmd("list-sessions")
+	case QueryScopeWindows, QueryScopePanes:
+		if scope == QueryScopeWindows {
+			q.cmd("list-windows")
+		} else {
+			q.cmd("list-panes")
+		}
+
+		if target == "" {
+			q.fargs("-a")
+		} else {
+			q.fargs("-t", target.arg())
+		}
+	case QueryScopeSessionPanes:
+		q.cmd("list-panes").fargs("-s", "-t", target.arg())
+	case QueryScopeClients:
+		q.cmd("list-clients")
+		if target != "" {
+			q.fargs("-t", target.arg())
+		}
+	default:
+		return nil, errors.New("invalid query scope")
+	}
+
+	// An empty variable ends every line, so that a line split by a newline in a value is detected.
+	o, err := q.vars(append(vars, "")...).run()
+	if err != nil {
+		return nil, errors.New("failed to run query")
+	}
+
+	results, err := o.collectStrict()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]T, 0, len(results))
+	for _, res := range results {
+		item, err := decodeResult[T](fields, res)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+
+	return out, nil
+}
+
+// Returns the fields of T with a tmux tag, and their variables.
+func queryFields[T any]() ([]decodeField, []string, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, nil, errors.New("query type must be a struct")
+	}
+
+	fields, err := decodeFields(typ)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vars := make([]string, 0, len(fields))
+	for _, f := range fields {
+		vars = append(vars, f.variable)
+	}
+
+	return fields, vars, nil
+}
+
+// Decodes a query result into a T.
+func decodeResult[T any](fields []decodeField, res queryResult) (T, error) {
+	var item T
+	v := reflect.ValueOf(&item).Elem()
+	for _, f := range fields {
+		err := decodeValue(v.Field(f.index), res.get(f.variable))
+		if err != nil {
+			return item, fmt.Errorf("failed to decode %s: %w", f.variable, err)
+		}
+	}
+
+	return item, nil
+}
+
+// Lists the fields of a struct with a tmux tag.
+func decodeFields(typ reflect.Type) ([]decodeField, error) {
+	fields := make([]decodeField, 0)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		variable, ok := f.Tag.Lookup("tmux")
+		if !ok || variable == "" || variable == "-" {
+			continue
+		}
+
+		if !f.IsExported() {
+			return nil, fmt.Errorf("field %s with tmux tag must be exported", f.Name)
+		}
+
+		if !decodable(f.Type) {
+			return nil, fmt.Errorf("unsupported type %s of field %s", f.Type, f.Name)
+		}
+
+		fields = append(fields, decodeField{index: i, variable: variable})
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("query type has no fields with a tmux tag")
+	}
+
+	return fields, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Returns true if values of the type can be decoded from tmux variables.
+func decodable(typ reflect.Type) bool {
+	if typ == timeType {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Slice:
+		return typ.Elem().Kind() == reflect.String
+	}
+
+	return false
+}
+
+// Decodes the value of a tmux variable into a field. Empty values decode to the zero value.
+func decodeValue(field reflect.Value, value string) error {
+	if value == "" {
+		field.SetZero()
+		return nil
+	}
+
+	if field.Type() == timeType {
+		v, err := parseTime(value)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		field.SetBool(value == "1")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Slice:
+		field.Set(reflect.ValueOf(parseList(value)).Convert(field.Type()))
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+)
+
+// Variable of a session or of the global environment.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#ENVIRONMENT
+type EnvironmentVariable struct {
+	Name  string
+	Value string
+
+	// Whether the variable is marked as removed, so that it is unset in new processes
+	// even if it is set in the global environment.
+	Removed bool
+
+	// Whether the variable is hidden, so that it is only available to formats and not to new processes.
+	Hidden bool
+}
+
+// Environment options.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-environment
+type EnvironmentOptions struct {
+	// Uses the global environment instead of the environment of the target session.
+	Global bool
+
+	// Sets or unsets a hidden variable. Listing always includes hidden variables.
+	Hidden bool
+
+	// Expands the value as a format when setting it.
+	Format bool
+}
+
+// Appends the scope flags of the options to the query.
+func (op *EnvironmentOptions) scopeArgs(q *query, target Target) {
+	if op != nil {
+		if op.Global {
+			q.fargs("-g")
+		}
+
+		if op.Hidden {
+			q.fargs("-h")
+		}
+	}
+
+	if (op == nil || !op.Global) && target != "" {
+		q.fargs("-t", target.arg())
+	}
+}
+
+// Retrieves the environment of the target session, or the global environment, by variable name.
+// Removed and hidden variables are included.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-environment
+func (t *Tmux) Environment(target Target, op *EnvironmentOptions) (map[string]*EnvironmentVariable, error) {
+	out := make(map[string]*EnvironmentVariable)
+	for _, hidden := range []bool{false, true} {
+		q := t.query().
+			cmd("show-environment")
+
+		scope := EnvironmentOptions{Hidden: hidden}
+		if op != nil {
+			scope.Global = op.Global
+		}
+		scope.scopeArgs(q, target)
+
+		o, err := q.run()
+		if err != nil {
+			return nil, errors.New("failed to retrieve environment")
+		}
+
+		for _, line := range strings.Split(o.raw(), "\n") {
+			v, ok := parseEnvironmentVariable(line)
+			if !ok {
+				continue
+			}
+			v.Hidden = hidden
+			out[v.Name] = v
+		}
+	}
+
+	return out, nil
+}
+
+// Retrieves the global environment, inherited by all sessions.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-environment
+func (t *Tmux) GlobalEnvironment() (map[string]*EnvironmentVariable, error) {
+	return t.Environment("", &EnvironmentOptions{Global: true})
+}
+
+// Parses a line of show-environment output, such as "NAME=value" or "-NAME" for removed variables.
+func parseEnvironmentVariable(line string) (*EnvironmentVariable, bool) {
+	if line == "" {
+		return nil, false
+	}
+
+	if name, ok := strings.CutPrefix(line, "-"); ok {
+		return &EnvironmentVariable{Name: name, Removed: true}, true
+	}
+
+	name, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return nil, false
+	}
+
+	return &EnvironmentVariable{Name: name, Value: value}, true
+}
+
+// Sets a variable in the environment of the target session, or in the global environment.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-environment
+func (t *Tmux) SetEnvironment(target Target, name, value string, op *EnvironmentOptions) error {
+	q := t.query().
+		cmd("set-environment")
+	op.scopeArgs(q, target)
+
+	if op != nil && op.Format {
+		q.fargs("-F")
+	}
+
+	_, err := q.pargs(name, value).run()
+	if err != nil {
+		return errors.New("failed to set environment variable")
+	}
+
+	return nil
+}
+
+// Unsets a variable in the environment of the target session, or in the global environment.
+// Unset session variables are inherited from the global environment again.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-environment
+func (t *Tmux) UnsetEnvironment(target Target, name string, op *EnvironmentOptions) error {
+	q := t.query().
+		cmd("set-environment").
+		fargs("-u")
+	op.scopeArgs(q, target)
+
+	_, err := q.pargs(name).run()
+	if err != nil {
+		return errors.New("failed to unset environment variable")
+	}
+
+	return nil
+}
+
+// Marks a variable as removed in the environment of the target session, or in the global environment.
+// Removed variables are unset in new processes, even if set in the global environment.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-environment
+func (t *Tmux) RemoveEnvironment(target Target, name string, op *EnvironmentOptions) error {
+	q := t.query().
+		cmd("set-environment").
+		fargs("-r")
+	op.scopeArgs(q, target)
+
+	_, err := q.pargs(name).run()
+	if err != nil {
+		return errors.New("failed to remove environment variable")
+	}
+
+	return nil
+}
+
+// Copies the variables listed in the update-environment option of the target session
+// from the environment of the current process, as tmux does when a client attaches.
+// Listed variables that are not set in the process are marked as removed.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#update-environment
+func (t *Tmux) SyncEnvironment(target Target) error {
+	patterns, err := t.OptionArray(target, "update-environment", OptionScopeSession)
+	if err != nil {
+		return err
+	}
+
+	environ := os.Environ()
+	for _, pattern := range patterns {
+		found := false
+		for _, e := range environ {
+			name, value, _ := strings.Cut(e, "=")
+			if ok, _ := path.Match(pattern, name); !ok {
+				continue
+			}
+
+			found = true
+			err := t.SetEnvironment(target, name, value, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Patterns are only removed as names when they are not globs.
+		if !found && !strings.ContainsAny(pattern, "*?[") {
+			err := t.RemoveEnvironment(target, pattern, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Syncs the environment of the current process into every session, for example after re-attaching over ssh.
+// See SyncEnvironment.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#update-environment
+func (t *Tmux) SyncAllEnvironments() error {
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		err := s.SyncEnvironment()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Retrieves the environment of the session by variable name.
+// Removed and hidden variables are included, variables inherited from the global environment are not.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-environment
+func (s *Session) Environment() (map[string]*EnvironmentVariable, error) {
+	return s.tmux.Environment(s.Target(), nil)
+}
+
+// Sets a variable in the environment of the session.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-environment
+func (s *Session) SetEnv(name, value string) error {
+	return s.tmux.SetEnvironment(s.Target(), name, value, nil)
+}
+
+// Unsets a variable in the environment of the session, so that it is inherited from the global environment.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-environment
+func (s *Session) UnsetEnv(name string) error {
+	return s.tmux.UnsetEnvironment(s.Target(), name, nil)
+}
+
+// Marks a variable as removed in the environment of the session.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-environment
+func (s *Session) RemoveEnv(name string) error {
+	return s.tmux.RemoveEnvironment(s.Target(), name, nil)
+}
+
+// Copies the variables listed in the update-environment option of the session from the environment of the current process.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#update-environment
+func (s *Session) SyncEnvironment() error {
+	return s.tmux.SyncEnvironment(s.Target())
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Separator of the fields of an event.
+const eventSep = "\x1f"
+
+// Receives events sent by tmux commands through a FIFO and dispatches them to Go handlers.
+// Commands are built with 'command', which expands the formats in the context of the command.
+type eventListener struct {
+	dir  string
+	fifo string
+	file *os.File
+
+	mu       sync.Mutex
+	handlers map[string]func(values []string)
+	done     chan struct{}
+}
+
+// Returns the event listener, starting it on first use.
+func (t *Tmux) eventListener() (*eventListener, error) {
+	t.eventsMu.Lock()
+	defer t.eventsMu.Unlock()
+	if t.events != nil {
+		return t.events, nil
+	}
+
+	dir, err := os.MkdirTemp("", "gotmux-events-")
+	if err != nil {
+		return nil, errors.New("failed to create event directory")
+	}
+
+	fifo := filepath.Join(dir, "fifo")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		os.RemoveAll(dir)
+		return nil, errors.New("failed to create event fifo")
+	}
+
+	// Opened for reading and writing so that it never blocks nor reaches the end.
+	f, err := os.OpenFile(fifo, os.O_RDWR, 0)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, errors.New("failed to open event fifo")
+	}
+
+	l := &eventListener{
+		dir:      dir,
+		fifo:     fifo,
+		file:     f,
+		handlers: make(map[string]func(values []string)),
+		done:     make(chan struct{}),
+	}
+	go l.listen()
+
+	t.events = l
+	return l, nil
+}
+
+// Reads events and dispatches them to their handler.
+func (l *eventListener) listen() {
+	defer close(l.done)
+	scanner := bufio.NewScanner(l.file)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), eventSep)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Every field is prefixed with a character so that empty values are kept by the shell.
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			if len(f) > 0 {
+				values[i] = f[1:]
+			}
+		}
+
+		l.mu.Lock()
+		handler := l.handlers[values[0]]
+		l.mu.Unlock()
+
+		if handler != nil {
+			handler(values[1:])
+		}
+	}
+}
+
+// Registers a handler for an event.
+func (l *eventListener) handle(id string, handler func(values []string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[id] = handler
+}
+
+// Removes the handler of an event.
+func (l *eventListener) remove(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.handlers, id)
+}
+
+// Returns a tmux command sending an event with the expanded values of the given formats variables.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#run-shell
+func (l *eventListener) command(id string, variables ...string) string {
+	args := []string{"x" + shellQuote(id)}
+	for _, v := range variables {
+		args = append(args, fmt.Sprintf("x#{q:%s}", v))
+	}
+
+	format := strings.Repeat("%s"+eventSep, len(args)-1) + "%s\\n"
+	shell := fmt.Sprintf("printf '%s' %s >> %s", format, strings.Join(args, " "), shellQuote(l.fifo))
+	return fmt.Sprintf("run-shell -b %s", tmuxQuote(shell))
+}
+
+// Stops the listener and removes the FIFO.
+func (l *eventListener) close() {
+	l.file.Close()
+	<-l.done
+	os.RemoveAll(l.dir)
+}
+
+// Stops the event listener used by Go key bindings and hooks.
+// The bindings and hooks routed to Go are not removed, they should be unbound first.
+func (t *Tmux) Close() error {
+	t.eventsMu.Lock()
+	defer t.eventsMu.Unlock()
+	if t.events != nil {
+		t.events.close()
+		t.events = nil
+	}
+
+	return nil
+}
+
+// Quotes a string as a single argument of a tmux command.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#PARSING_SYNTAX
+func tmuxQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+	return `"` + r.Replace(s) + `"`
+}
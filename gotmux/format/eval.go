@@ -0,0 +1,600 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package format
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Variables a format is evaluated against, such as a snapshot of a pane.
+// Sessions, Windows and Panes are used by the loops #{S:}, #{W:} and #{P:}.
+// Variables missing in a loop item are looked up in the enclosing context.
+type Context struct {
+	Vars     map[string]string
+	Sessions []*Context
+	Windows  []*Context
+	Panes    []*Context
+
+	parent *Context
+}
+
+// Single character aliases, such as #S for #{session_name}.
+var aliases = map[byte]string{
+	'D': "pane_id",
+	'F': "window_flags",
+	'H': "host",
+	'h': "host_short",
+	'I': "window_index",
+	'P': "pane_index",
+	'S': "session_name",
+	'T': "pane_title",
+	'W': "window_name",
+}
+
+// Characters escaped by the q modifier.
+const shellSpecial = "|&;<>()$`\\\"'*?[# =%"
+
+// Evaluates a format against the context, as tmux would.
+// Shell commands #() are not supported and styles #[] are kept as is.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#FORMATS
+func Evaluate(f Format, ctx *Context) (string, error) {
+	if ctx == nil {
+		ctx = &Context{}
+	}
+
+	return ctx.expand(string(f))
+}
+
+// Looks up a variable in the context and the enclosing contexts.
+func (c *Context) lookup(name string) string {
+	for ctx := c; ctx != nil; ctx = ctx.parent {
+		if v, ok := ctx.Vars[name]; ok {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// Finds the items of a loop in the context or the enclosing contexts.
+func (c *Context) items(kind byte) []*Context {
+	for ctx := c; ctx != nil; ctx = ctx.parent {
+		var items []*Context
+		switch kind {
+		case 'S':
+			items = ctx.Sessions
+		case 'W':
+			items = ctx.Windows
+		case 'P':
+			items = ctx.Panes
+		}
+
+		if items != nil {
+			return items
+		}
+	}
+
+	return nil
+}
+
+// Expands a format.
+func (c *Context) expand(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '#' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		next := s[i+1]
+		switch {
+		case next == '#' || next == ',' || next == '}':
+			b.WriteByte(next)
+			i++
+		case next == '{':
+			end := skip(s, i+2)
+			if end == -1 {
+				return "", errors.New("unterminated format")
+			}
+
+			v, err := c.replace(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(v)
+			i = end
+		case next == '(':
+			return "", errors.New("shell commands are not supported")
+		case aliases[next] != "":
+			b.WriteString(c.lookup(aliases[next]))
+			i++
+		default:
+			b.WriteByte('#')
+		}
+	}
+
+	return b.String(), nil
+}
+
+// Returns the index of the brace closing the format starting at start, or -1.
+func skip(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '#' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '#' && i+1 < len(s):
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Splits the arguments of a modifier on commas outside nested formats.
+func splitArgs(s string) []string {
+	out := make([]string, 0)
+	last := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '#' && i+1 < len(s) && s[i+1] == '{':
+			end := skip(s, i+2)
+			if end == -1 {
+				i = len(s)
+			} else {
+				i = end
+			}
+		case s[i] == '#' && i+1 < len(s):
+			i++
+		case s[i] == ',':
+			out = append(out, s[last:i])
+			last = i + 1
+		}
+	}
+
+	return append(out, s[last:])
+}
+
+// Returns true if the value is true in a condition.
+func truthy(v string) bool {
+	return v != "" && v != "0"
+}
+
+// Converts a boolean to a format value.
+func boolValue(b bool) string {
+	if b {
+		return "1"
+	}
+
+	return "0"
+}
+
+// Evaluates a value that is a variable name or, if it contains formats, a format.
+func (c *Context) value(s string) (string, error) {
+	if strings.Contains(s, "#{") {
+		return c.expand(s)
+	}
+
+	return c.lookup(s), nil
+}
+
+// Replaces the content of a #{} format.
+func (c *Context) replace(body string) (string, error) {
+	if rest, ok := strings.CutPrefix(body, "?"); ok {
+		return c.conditional(rest)
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "&&", "||"} {
+		if rest, ok := strings.CutPrefix(body, op+":"); ok {
+			return c.compare(op, rest)
+		}
+	}
+
+	if strings.HasPrefix(body, "m:") || strings.HasPrefix(body, "m/") {
+		flags, rest, ok := strings.Cut(body[1:], ":")
+		if ok {
+			return c.match(strings.TrimPrefix(flags, "/"), rest)
+		}
+	}
+
+	if len(body) > 1 && body[1] == ':' && strings.IndexByte("SWP", body[0]) != -1 {
+		return c.loop(body[0], body[2:])
+	}
+
+	mods, rest, ok := parseModifiers(body)
+	if !ok {
+		return c.lookup(body), nil
+	}
+
+	return c.modify(mods, rest)
+}
+
+// Evaluates #{?cond,a,b}, and chains such as #{?cond1,a,cond2,b,c}.
+func (c *Context) conditional(s string) (string, error) {
+	args := splitArgs(s)
+	if len(args) < 2 {
+		return "", errors.New("invalid conditional")
+	}
+
+	for len(args) >= 2 {
+		cond, err := c.value(args[0])
+		if err != nil {
+			return "", err
+		}
+
+		if truthy(cond) {
+			return c.expand(args[1])
+		}
+
+		args = args[2:]
+	}
+
+	if len(args) == 1 {
+		return c.expand(args[0])
+	}
+
+	return "", nil
+}
+
+// Evaluates comparisons and logical operators.
+func (c *Context) compare(op, s string) (string, error) {
+	args := splitArgs(s)
+	if len(args) != 2 {
+		return "", fmt.Errorf("invalid %s comparison", op)
+	}
+
+	a, err := c.expand(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	b, err := c.expand(args[1])
+	if err != nil {
+		return "", err
+	}
+
+	switch op {
+	case "==":
+		return boolValue(a == b), nil
+	case "!=":
+		return boolValue(a != b), nil
+	case "<":
+		return boolValue(a < b), nil
+	case ">":
+		return boolValue(a > b), nil
+	case "<=":
+		return boolValue(a <= b), nil
+	case ">=":
+		return boolValue(a >= b), nil
+	case "&&":
+		return boolValue(truthy(a) && truthy(b)), nil
+	default:
+		return boolValue(truthy(a) || truthy(b)), nil
+	}
+}
+
+// Evaluates #{m:pattern,s} with the r (regular expression) and i (ignore case) flags.
+func (c *Context) match(flags, s string) (string, error) {
+	args := splitArgs(s)
+	if len(args) != 2 {
+		return "", errors.New("invalid match")
+	}
+
+	pattern, err := c.expand(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	text, err := c.expand(args[1])
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.Contains(flags, "r") {
+		pattern = globToRegex(pattern)
+	}
+
+	if strings.Contains(flags, "i") {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", errors.New("invalid match pattern")
+	}
+
+	return boolValue(re.MatchString(text)), nil
+}
+
+// Converts a shell glob to an anchored regular expression.
+func globToRegex(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch ch := glob[i]; ch {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end == -1 {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// Evaluates the loops #{S:}, #{W:} and #{P:}.
+// Windows and panes use the second format, if given, for the current window and active pane.
+func (c *Context) loop(kind byte, s string) (string, error) {
+	f, current := s, ""
+	if args := splitArgs(s); len(args) == 2 && kind != 'S' {
+		f, current = args[0], args[1]
+	}
+
+	var b strings.Builder
+	for _, item := range c.items(kind) {
+		child := &Context{
+			Vars:     item.Vars,
+			Sessions: item.Sessions,
+			Windows:  item.Windows,
+			Panes:    item.Panes,
+			parent:   c,
+		}
+
+		use := f
+		if current != "" {
+			active := "window_active"
+			if kind == 'P' {
+				active = "pane_active"
+			}
+
+			if item.Vars[active] == "1" {
+				use = current
+			}
+		}
+
+		v, err := child.expand(use)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+	}
+
+	return b.String(), nil
+}
+
+// A modifier of a format, such as t, =10 or s/foo/bar/.
+type modifier struct {
+	name string
+	args []string
+}
+
+// Parses the modifiers of a format, separated by ; and ending with :.
+// Returns false if the format has no modifiers.
+func parseModifiers(body string) ([]modifier, string, bool) {
+	mods := make([]modifier, 0)
+	i := 0
+	for i < len(body) {
+		var m modifier
+		switch ch := body[i]; {
+		case strings.IndexByte("qnbdlETt", ch) != -1:
+			m.name = string(ch)
+			i++
+			// Flags such as t/p are accepted and ignored.
+			if m.name == "t" && i < len(body) && body[i] == '/' {
+				end := strings.IndexAny(body[i:], ":;")
+				if end == -1 {
+					return nil, "", false
+				}
+				i += end
+			}
+		case ch == '=' || ch == 'p':
+			m.name = string(ch)
+			i++
+			start := i
+			if i < len(body) && body[i] == '-' {
+				i++
+			}
+			for i < len(body) && body[i] >= '0' && body[i] <= '9' {
+				i++
+			}
+			if i == start {
+				return nil, "", false
+			}
+			m.args = []string{body[start:i]}
+		case ch == 's':
+			if i+1 >= len(body) {
+				return nil, "", false
+			}
+
+			sep := string(body[i+1])
+			parts := strings.SplitN(body[i+2:], sep, 3)
+			if len(parts) != 3 {
+				return nil, "", false
+			}
+
+			flags := parts[2]
+			end := strings.IndexAny(flags, ":;")
+			if end == -1 {
+				return nil, "", false
+			}
+
+			m.name = "s"
+			m.args = []string{parts[0], parts[1], flags[:end]}
+			i += 2 + len(parts[0]) + len(parts[1]) + 2 + end
+		default:
+			return nil, "", false
+		}
+
+		mods = append(mods, m)
+		if i >= len(body) {
+			return nil, "", false
+		}
+
+		switch body[i] {
+		case ':':
+			return mods, body[i+1:], true
+		case ';':
+			i++
+		default:
+			return nil, "", false
+		}
+	}
+
+	return nil, "", false
+}
+
+// Returns true if the modifiers contain the named modifier.
+func hasModifier(mods []modifier, name string) bool {
+	for _, m := range mods {
+		if m.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Applies modifiers to the value of the operand.
+func (c *Context) modify(mods []modifier, operand string) (string, error) {
+	var v string
+	var err error
+	switch {
+	case hasModifier(mods, "l"):
+		v = operand
+	case hasModifier(mods, "E") || hasModifier(mods, "T"):
+		v, err = c.value(operand)
+		if err == nil {
+			v, err = c.expand(v)
+		}
+	default:
+		v, err = c.value(operand)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// As in tmux, the time, path and quoting modifiers only apply to variables, not to nested formats.
+	nested := !hasModifier(mods, "l") && strings.Contains(operand, "#{")
+
+	order := []string{"t", "b", "d", "q", "s", "n", "=", "p"}
+	for _, name := range order {
+		if nested && strings.Contains("tbdq", name) {
+			continue
+		}
+
+		for _, m := range mods {
+			if m.name != name {
+				continue
+			}
+
+			v, err = applyModifier(m, v)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return v, nil
+}
+
+// Applies a single modifier to a value.
+func applyModifier(m modifier, v string) (string, error) {
+	switch m.name {
+	case "t":
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return "", nil
+		}
+		return time.Unix(n, 0).Format("Mon Jan _2 15:04:05 2006"), nil
+	case "b":
+		if v == "" {
+			return "", nil
+		}
+		return path.Base(v), nil
+	case "d":
+		if v == "" {
+			return "", nil
+		}
+		return path.Dir(v), nil
+	case "q":
+		var b strings.Builder
+		for _, r := range v {
+			if strings.ContainsRune(shellSpecial, r) {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		return b.String(), nil
+	case "s":
+		pattern := m.args[0]
+		if strings.Contains(m.args[2], "i") {
+			pattern = "(?i)" + pattern
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", errors.New("invalid substitution pattern")
+		}
+
+		// The replacement is literal apart from the \N group references.
+		repl := strings.ReplaceAll(m.args[1], "$", "$$")
+		repl = regexp.MustCompile(`\\(\d)`).ReplaceAllString(repl, "$${$1}")
+		return re.ReplaceAllString(v, repl), nil
+	case "n":
+		return strconv.Itoa(utf8.RuneCountInString(v)), nil
+	case "=":
+		n, _ := strconv.Atoi(m.args[0])
+		runes := []rune(v)
+		switch {
+		case n > 0 && n < len(runes):
+			return string(runes[:n]), nil
+		case n < 0 && -n < len(runes):
+			return string(runes[len(runes)+n:]), nil
+		}
+		return v, nil
+	case "p":
+		n, _ := strconv.Atoi(m.args[0])
+		width := utf8.RuneCountInString(v)
+		switch {
+		case n > width:
+			return v + strings.Repeat(" ", n-width), nil
+		case -n > width:
+			return strings.Repeat(" ", -n-width) + v, nil
+		}
+		return v, nil
+	}
+
+	return v, nil
+}
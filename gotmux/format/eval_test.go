@@ -0,0 +1,215 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package format
+
+import "testing"
+
+var testVars = map[string]string{
+	"name":    "hello",
+	"n":       "5",
+	"empty":   "",
+	"zero":    "0",
+	"path":    "/usr/local/bin",
+	"special": "a b;c",
+	"nested":  "#{path}",
+	"dollar":  "a$b",
+	"created": "1792354426",
+}
+
+// The expected values match the output of display-message for the same variables.
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		f    string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"#{name}", "hello"},
+		{"#{missing}", ""},
+		{"##{name}", "#{name}"},
+		{"a ## b", "a # b"},
+		{"100%", "100%"},
+		{"#[fg=red]#{name}", "#[fg=red]hello"},
+		{"#{?name,yes,no}", "yes"},
+		{"#{?empty,yes,no}", "no"},
+		{"#{?zero,yes,no}", "no"},
+		{"#{?missing,yes}", ""},
+		{"#{?name,#{n},x}", "5"},
+		{"#{?name,a#,b,c}", "a,b"},
+		{"#{?#{==:#{name},hello},same,diff}", "same"},
+		// Chained conditionals are not supported by older servers.
+		{"#{?empty,a,zero,b,c}", "c"},
+		{"#{?empty,a,name,b,c}", "b"},
+		{"#{==:#{name},hello}", "1"},
+		{"#{!=:#{name},hello}", "0"},
+		{"#{<:abc,abd}", "1"},
+		{"#{>=:#{n},5}", "1"},
+		{"#{&&:#{name},#{zero}}", "0"},
+		{"#{||:#{empty},#{name}}", "1"},
+		{"#{m:h*o,#{name}}", "1"},
+		{"#{m:H*,#{name}}", "0"},
+		{"#{m/i:H*,#{name}}", "1"},
+		{"#{m/r:^h.l+o$,#{name}}", "1"},
+		{"#{m:[!x]ello,#{name}}", "1"},
+		{"#{m:h?llo,#{name}}", "1"},
+		{"#{=3:name}", "hel"},
+		{"#{=-3:name}", "llo"},
+		{"#{=10:name}", "hello"},
+		{"#{p7:name}|", "hello  |"},
+		{"#{p-7:name}|", "  hello|"},
+		{"#{=2;p4:name}|", "he  |"},
+		{"#{n:name}", "5"},
+		{"#{b:path}", "bin"},
+		{"#{d:path}", "/usr/local"},
+		{"#{b;s/b/B/:path}", "Bin"},
+		{"#{b:missing}", ""},
+		{"#{d:missing}", ""},
+		{"#{n:missing}", "0"},
+		{"#{q:special}", `a\ b\;c`},
+		{"#{s/l/L/:name}", "heLLo"},
+		{"#{s/L/x/i:name}", "hexxo"},
+		{`#{s/(h)(e)/\2\1/:name}`, "ehllo"},
+		{"#{s|l|_|:name}", "he__o"},
+		{"#{s/a/$HOME/:dollar}", "$HOME$b"},
+		{"#{s/a/$1/:dollar}", "$1$b"},
+		{"#{s/a/x/:#{dollar}}", "x$b"},
+		{"#{q:dollar}", `a\$b`},
+		{"#{q:#{dollar}}", "a$b"},
+		{"#{b:#{path}}", "/usr/local/bin"},
+		{"#{d:#{path}}", "/usr/local/bin"},
+		{"#{t:#{created}}", "1792354426"},
+		{"#{b;s/b/B/:#{path}}", "/usr/local/Bin"},
+		{"#{n:#{path}}", "14"},
+		{"#{=3:#{path}}", "/us"},
+		{"#{l:name}", "name"},
+		{"#{nested}", "#{path}"},
+		{"#{E:nested}", "/usr/local/bin"},
+		{"#{T:nested}", "/usr/local/bin"},
+	}
+
+	ctx := &Context{Vars: testVars}
+	for _, tt := range tests {
+		t.Run(tt.f, func(t *testing.T) {
+			got, err := Evaluate(Format(tt.f), ctx)
+			if err != nil {
+				t.Fatalf("Evaluate(%q) error: %v", tt.f, err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate(%q) = %q, want %q", tt.f, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateAliases(t *testing.T) {
+	ctx := &Context{Vars: map[string]string{
+		"session_name": "main",
+		"window_index": "1",
+		"pane_id":      "%3",
+	}}
+
+	got, err := Evaluate("#S:#I #D #X", ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "main:1 %3 #X" {
+		t.Errorf("Evaluate = %q, want %q", got, "main:1 %3 #X")
+	}
+}
+
+func TestEvaluateLoops(t *testing.T) {
+	ctx := &Context{
+		Vars: map[string]string{"session_name": "main"},
+		Sessions: []*Context{
+			{Vars: map[string]string{"session_name": "a"}},
+			{Vars: map[string]string{"session_name": "b"}},
+		},
+		Windows: []*Context{
+			{
+				Vars:  map[string]string{"window_name": "one", "window_active": "0"},
+				Panes: []*Context{{Vars: map[string]string{"pane_id": "%1", "pane_active": "1"}}},
+			},
+			{
+				Vars: map[string]string{"window_name": "two", "window_active": "1"},
+				Panes: []*Context{
+					{Vars: map[string]string{"pane_id": "%2", "pane_active": "0"}},
+					{Vars: map[string]string{"pane_id": "%3", "pane_active": "1"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		f    string
+		want string
+	}{
+		{"#{S:#{session_name} }", "a b "},
+		{"#{W:#{window_name} }", "one two "},
+		{"#{W:#{window_name} ,[#{window_name}] }", "one [two] "},
+		{"#{W:#{window_name}@#{session_name} }", "one@main two@main "},
+		{"#{W:#{P:#{pane_id},*#{pane_id}} }", "*%1 %2*%3 "},
+		{"#{P:#{pane_id}}", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.f, func(t *testing.T) {
+			got, err := Evaluate(Format(tt.f), ctx)
+			if err != nil {
+				t.Fatalf("Evaluate(%q) error: %v", tt.f, err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate(%q) = %q, want %q", tt.f, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	for _, f := range []string{"#{name", "#(date)", "#{?name}", "#{==:a}", "#{m/r:(,a}", "#{s/(/x/:name}"} {
+		if got, err := Evaluate(Format(f), &Context{Vars: testVars}); err == nil {
+			t.Errorf("Evaluate(%q) = %q, want error", f, got)
+		}
+	}
+}
+
+// Formats built with the builder evaluate to the expected values.
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		f    Format
+		str  string
+		want string
+	}{
+		{Var("name"), "#{name}", "hello"},
+		{Concat(Text("#"), Var("n")), "###{n}", "#5"},
+		{If(Var("empty"), Text("a,b"), Text("c}")), "#{?empty,a#,b,c#}}", "c}"},
+		{Eq(Var("name"), Text("hello")), "#{==:#{name},hello}", "1"},
+		{And(Var("name"), Var("zero")), "#{&&:#{name},#{zero}}", "0"},
+		{Truncate(2, Var("name")), "#{=2:name}", "he"},
+		{Pad(-6, Var("name")), "#{p-6:name}", " hello"},
+		{Length(Var("path")), "#{n:path}", "14"},
+		{Basename(Var("path")), "#{b:path}", "bin"},
+		{Quote(Var("special")), "#{q:special}", `a\ b\;c`},
+		{Substitute("l", "L", Var("name")), "#{s/l/L/:name}", "heLLo"},
+		{Substitute("/", "|", Var("path")), "#{s!/!|!:path}", "|usr|local|bin"},
+		{Expand("nested"), "#{E:nested}", "/usr/local/bin"},
+		{Dirname(Var("missing")), "#{d:missing}", ""},
+	}
+
+	ctx := &Context{Vars: testVars}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if tt.f.String() != tt.str {
+				t.Errorf("String() = %q, want %q", tt.f.String(), tt.str)
+			}
+
+			got, err := Evaluate(tt.f, ctx)
+			if err != nil {
+				t.Fatalf("Evaluate(%q) error: %v", tt.f, err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate(%q) = %q, want %q", tt.f, got, tt.want)
+			}
+		})
+	}
+}
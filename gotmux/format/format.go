@@ -0,0 +1,245 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+// Package format builds tmux format expressions and evaluates them locally.
+// Expressions can be evaluated by the server with Tmux.DisplayFormat.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#FORMATS
+package format
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A tmux format expression.
+type Format string
+
+// Returns the expression as a string.
+func (f Format) String() string {
+	return string(f)
+}
+
+// Returns the variable name if the format is a single variable, such as #{pane_id}.
+func (f Format) varName() (string, bool) {
+	s := string(f)
+	if !strings.HasPrefix(s, "#{") || !strings.HasSuffix(s, "}") {
+		return "", false
+	}
+
+	name := s[2 : len(s)-1]
+	if name == "" {
+		return "", false
+	}
+
+	for _, r := range name {
+		if !(r == '_' || r == '@' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return "", false
+		}
+	}
+
+	return name, true
+}
+
+// Returns the operand of a modifier. Variables are referred to by name, other formats are expanded.
+func (f Format) operand() string {
+	if name, ok := f.varName(); ok {
+		return name
+	}
+
+	return string(f)
+}
+
+// A variable, such as #{session_name}.
+func Var(name string) Format {
+	return Format("#{" + name + "}")
+}
+
+// Literal text. Characters with a special meaning in formats are escaped.
+func Text(s string) Format {
+	r := strings.NewReplacer("#", "##", ",", "#,", "}", "#}")
+	return Format(r.Replace(s))
+}
+
+// Concatenates formats.
+func Concat(parts ...Format) Format {
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(string(p))
+	}
+
+	return Format(b.String())
+}
+
+// A style, such as #[fg=red,bold].
+func Style(style string) Format {
+	return Format("#[" + style + "]")
+}
+
+// Conditional, #{?cond,then,else}. The condition is true if it is not empty and not 0.
+func If(cond, then, otherwise Format) Format {
+	c := string(cond)
+	if name, ok := cond.varName(); ok {
+		c = name
+	}
+
+	return Format("#{?" + c + "," + string(then) + "," + string(otherwise) + "}")
+}
+
+// Builds a comparison or logical operator with two operands.
+func binary(op string, a, b Format) Format {
+	return Format("#{" + op + ":" + string(a) + "," + string(b) + "}")
+}
+
+// String equality, #{==:a,b}.
+func Eq(a, b Format) Format {
+	return binary("==", a, b)
+}
+
+// String inequality, #{!=:a,b}.
+func Ne(a, b Format) Format {
+	return binary("!=", a, b)
+}
+
+// String comparison, #{<:a,b}.
+func Lt(a, b Format) Format {
+	return binary("<", a, b)
+}
+
+// String comparison, #{>:a,b}.
+func Gt(a, b Format) Format {
+	return binary(">", a, b)
+}
+
+// String comparison, #{<=:a,b}.
+func Le(a, b Format) Format {
+	return binary("<=", a, b)
+}
+
+// String comparison, #{>=:a,b}.
+func Ge(a, b Format) Format {
+	return binary(">=", a, b)
+}
+
+// Logical and, #{&&:a,b}.
+func And(a, b Format) Format {
+	return binary("&&", a, b)
+}
+
+// Logical or, #{||:a,b}.
+func Or(a, b Format) Format {
+	return binary("||", a, b)
+}
+
+// Options of pattern matching.
+type MatchOptions struct {
+	// Pattern is a regular expression instead of a glob.
+	Regex bool
+
+	// Case insensitive matching.
+	IgnoreCase bool
+}
+
+// Pattern matching, #{m:pattern,s}. Expands to 1 if s matches the pattern and 0 otherwise.
+func Match(pattern, s Format, op *MatchOptions) Format {
+	m := "m"
+	if op != nil && (op.Regex || op.IgnoreCase) {
+		m += "/"
+		if op.Regex {
+			m += "r"
+		}
+
+		if op.IgnoreCase {
+			m += "i"
+		}
+	}
+
+	return binary(m, pattern, s)
+}
+
+// Loop over all sessions, #{S:f}.
+func Sessions(f Format) Format {
+	return Format("#{S:" + string(f) + "}")
+}
+
+// Loop over all windows of the session, #{W:f}. Current is used for the current window if not empty.
+func Windows(f, current Format) Format {
+	return loop("W", f, current)
+}
+
+// Loop over all panes of the window, #{P:f}. Current is used for the active pane if not empty.
+func Panes(f, current Format) Format {
+	return loop("P", f, current)
+}
+
+// Builds a loop with an optional alternative for the current item.
+func loop(kind string, f, current Format) Format {
+	if current == "" {
+		return Format("#{" + kind + ":" + string(f) + "}")
+	}
+
+	return Format("#{" + kind + ":" + string(f) + "," + string(current) + "}")
+}
+
+// Formats a unix time variable as a date, #{t:f}.
+// The server only applies it to a variable, other formats are returned unchanged.
+func Time(f Format) Format {
+	return Format("#{t:" + f.operand() + "}")
+}
+
+// Truncates to n characters, #{=n:f}. A negative n keeps the last characters.
+func Truncate(n int, f Format) Format {
+	return Format("#{=" + strconv.Itoa(n) + ":" + f.operand() + "}")
+}
+
+// Pads to n characters, #{pn:f}. A negative n pads on the left.
+func Pad(n int, f Format) Format {
+	return Format("#{p" + strconv.Itoa(n) + ":" + f.operand() + "}")
+}
+
+// Delimiters of the substitution modifier, tried in order.
+const substituteDelimiters = "/|!@%^&~+=_"
+
+// Replaces the matches of a regular expression, #{s/pattern/replacement/:f}.
+// The delimiter is the first of / | ! @ % ^ & ~ + = _ that is not in the pattern or the replacement,
+// since tmux does not allow escaping it.
+func Substitute(pattern, replacement string, f Format) Format {
+	delim := "/"
+	for _, d := range substituteDelimiters {
+		if !strings.ContainsRune(pattern, d) && !strings.ContainsRune(replacement, d) {
+			delim = string(d)
+			break
+		}
+	}
+
+	return Format("#{s" + delim + pattern + delim + replacement + delim + ":" + f.operand() + "}")
+}
+
+// Escapes shell special characters, #{q:f}.
+// The server only applies it to a variable, other formats are returned unchanged.
+func Quote(f Format) Format {
+	return Format("#{q:" + f.operand() + "}")
+}
+
+// Length of the value, #{n:f}.
+func Length(f Format) Format {
+	return Format("#{n:" + f.operand() + "}")
+}
+
+// Base name of a path, #{b:f}.
+// The server only applies it to a variable, other formats are returned unchanged.
+func Basename(f Format) Format {
+	return Format("#{b:" + f.operand() + "}")
+}
+
+// Directory name of a path, #{d:f}.
+// The server only applies it to a variable, other formats are returned unchanged.
+func Dirname(f Format) Format {
+	return Format("#{d:" + f.operand() + "}")
+}
+
+// Expands the value of a variable as a format, #{E:name}.
+func Expand(name string) Format {
+	return Format("#{E:" + name + "}")
+}
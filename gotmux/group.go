@@ -0,0 +1,134 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+)
+
+// Group of sessions sharing the same set of windows.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#new-session
+type SessionGroup struct {
+	Name     string
+	Sessions []*Session
+}
+
+// Lists the session groups of the server.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#list-sessions
+func (t *Tmux) ListSessionGroups() ([]*SessionGroup, error) {
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*SessionGroup, 0)
+	groups := make(map[string]*SessionGroup)
+	for _, s := range sessions {
+		if !s.Grouped {
+			continue
+		}
+
+		g, ok := groups[s.Group]
+		if !ok {
+			g = &SessionGroup{Name: s.Group}
+			groups[s.Group] = g
+			out = append(out, g)
+		}
+		g.Sessions = append(g.Sessions, s)
+	}
+
+	return out, nil
+}
+
+// Gets a session group by name. Returns nil if the group does not exist.
+func (t *Tmux) GetSessionGroup(name string) (*SessionGroup, error) {
+	groups, err := t.ListSessionGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range groups {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Kills every session of the group by id, so that no other session is matched by name.
+// The windows of the group are destroyed with its last session.
+// All sessions are attempted even if some fail.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#kill-session
+func (t *Tmux) KillSessionGroup(name string) error {
+	g, err := t.GetSessionGroup(name)
+	if err != nil {
+		return err
+	}
+
+	if g == nil {
+		return errors.New("session group not found")
+	}
+
+	return g.Kill()
+}
+
+// Kills every session of the group.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#kill-session
+func (g *SessionGroup) Kill() error {
+	failed := false
+	for _, s := range g.Sessions {
+		if err := s.Kill(); err != nil {
+			failed = true
+		}
+	}
+
+	if failed {
+		return errors.New("failed to kill session group")
+	}
+
+	return nil
+}
+
+// Creates a new session grouped with this session, sharing its windows.
+// Pass nil to create it with default options.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#new-session
+func (s *Session) NewGroupedSession(op *SessionOptions) (*Session, error) {
+	opts := SessionOptions{}
+	if op != nil {
+		opts = *op
+	}
+	opts.GroupWith = s.Id
+
+	grouped, err := s.tmux.NewSession(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The session joins the group of the new session, creating it if needed.
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+
+	return grouped, nil
+}
+
+// Gets the group of this session. Returns nil if the session is not grouped.
+func (s *Session) SessionGroup() (*SessionGroup, error) {
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+
+	if !s.Grouped {
+		return nil, nil
+	}
+
+	return s.tmux.GetSessionGroup(s.Group)
+}
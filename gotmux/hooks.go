@@ -0,0 +1,435 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Tmux hook name.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#HOOKS
+type HookName string
+
+// Enumeration of hook names.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#HOOKS
+const (
+	HookAfterBindKey         HookName = "after-bind-key"
+	HookAfterCapturePane     HookName = "after-capture-pane"
+	HookAfterCopyMode        HookName = "after-copy-mode"
+	HookAfterDisplayMessage  HookName = "after-display-message"
+	HookAfterDisplayPanes    HookName = "after-display-panes"
+	HookAfterKillPane        HookName = "after-kill-pane"
+	HookAfterListBuffers     HookName = "after-list-buffers"
+	HookAfterListClients     HookName = "after-list-clients"
+	HookAfterListKeys        HookName = "after-list-keys"
+	HookAfterListPanes       HookName = "after-list-panes"
+	HookAfterListSessions    HookName = "after-list-sessions"
+	HookAfterListWindows     HookName = "after-list-windows"
+	HookAfterLoadBuffer      HookName = "after-load-buffer"
+	HookAfterLockServer      HookName = "after-lock-server"
+	HookAfterNewSession      HookName = "after-new-session"
+	HookAfterNewWindow       HookName = "after-new-window"
+	HookAfterPasteBuffer     HookName = "after-paste-buffer"
+	HookAfterPipePane        HookName = "after-pipe-pane"
+	HookAfterQueue           HookName = "after-queue"
+	HookAfterRefreshClient   HookName = "after-refresh-client"
+	HookAfterRenameSession   HookName = "after-rename-session"
+	HookAfterRenameWindow    HookName = "after-rename-window"
+	HookAfterResizePane      HookName = "after-resize-pane"
+	HookAfterResizeWindow    HookName = "after-resize-window"
+	HookAfterSaveBuffer      HookName = "after-save-buffer"
+	HookAfterSelectLayout    HookName = "after-select-layout"
+	HookAfterSelectPane      HookName = "after-select-pane"
+	HookAfterSelectWindow    HookName = "after-select-window"
+	HookAfterSendKeys        HookName = "after-send-keys"
+	HookAfterSetBuffer       HookName = "after-set-buffer"
+	HookAfterSetEnvironment  HookName = "after-set-environment"
+	HookAfterSetHook         HookName = "after-set-hook"
+	HookAfterSetOption       HookName = "after-set-option"
+	HookAfterShowEnvironment HookName = "after-show-environment"
+	HookAfterShowMessages    HookName = "after-show-messages"
+	HookAfterShowOptions     HookName = "after-show-options"
+	HookAfterSplitWindow     HookName = "after-split-window"
+	HookAfterUnbindKey       HookName = "after-unbind-key"
+	HookAlertActivity        HookName = "alert-activity"
+	HookAlertBell            HookName = "alert-bell"
+	HookAlertSilence         HookName = "alert-silence"
+	HookClientActive         HookName = "client-active"
+	HookClientAttached       HookName = "client-attached"
+	HookClientDetached       HookName = "client-detached"
+	HookClientFocusIn        HookName = "client-focus-in"
+	HookClientFocusOut       HookName = "client-focus-out"
+	HookClientResized        HookName = "client-resized"
+	HookClientSessionChanged HookName = "client-session-changed"
+	HookPaneDied             HookName = "pane-died"
+	HookPaneExited           HookName = "pane-exited"
+	HookPaneFocusIn          HookName = "pane-focus-in"
+	HookPaneFocusOut         HookName = "pane-focus-out"
+	HookPaneModeChanged      HookName = "pane-mode-changed"
+	HookPaneSetClipboard     HookName = "pane-set-clipboard"
+	HookPaneTitleChanged     HookName = "pane-title-changed"
+	HookSessionClosed        HookName = "session-closed"
+	HookSessionCreated       HookName = "session-created"
+	HookSessionRenamed       HookName = "session-renamed"
+	HookSessionWindowChanged HookName = "session-window-changed"
+	HookWindowLayoutChanged  HookName = "window-layout-changed"
+	HookWindowLinked         HookName = "window-linked"
+	HookWindowPaneChanged    HookName = "window-pane-changed"
+	HookWindowRenamed        HookName = "window-renamed"
+	HookWindowResized        HookName = "window-resized"
+	HookWindowUnlinked       HookName = "window-unlinked"
+)
+
+// Returns the hook run after a command, such as 'after-new-window' for 'new-window'.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#HOOKS
+func AfterHook(command string) HookName {
+	return HookName("after-" + command)
+}
+
+// Tmux hook object. A hook is an array of commands run when an event occurs.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#HOOKS
+type Hook struct {
+	Name    HookName
+	Index   int
+	Command string
+
+	target  Target
+	scope   HookOptions
+	eventId string
+	tmux    *Tmux
+}
+
+// Hook options.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-hook
+type HookOptions struct {
+	// Uses the global hooks instead of the hooks of the target.
+	Global bool
+
+	// Uses the window or pane hooks instead of the session hooks.
+	Window bool
+	Pane   bool
+
+	// Appends the command to the hook instead of replacing its commands.
+	Append bool
+
+	// Index of the command in the hook array to set, if not appending.
+	// All the commands of the hook are replaced if nil.
+	Index *int
+}
+
+// Appends the scope flags of the options to the query.
+func (op *HookOptions) scopeArgs(q *query, target Target) {
+	if op != nil {
+		if op.Global {
+			q.fargs("-g")
+		}
+
+		if op.Window {
+			q.fargs("-w")
+		}
+
+		if op.Pane {
+			q.fargs("-p")
+		}
+	}
+
+	if (op == nil || !op.Global) && target != "" {
+		q.fargs("-t", target.arg())
+	}
+}
+
+// Sets a hook command for the target. The command replaces all the commands of the hook,
+// unless it is appended or an index is set in the options.
+// The target is ignored for global hooks.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-hook
+func (t *Tmux) SetHook(target Target, name HookName, command string, op *HookOptions) error {
+	q := t.query().
+		cmd("set-hook")
+	op.scopeArgs(q, target)
+
+	hook := string(name)
+	switch {
+	case op != nil && op.Append:
+		q.fargs("-a")
+	case op != nil && op.Index != nil:
+		hook = fmt.Sprintf("%s[%d]", name, *op.Index)
+	}
+
+	_, err := q.pargs(hook, command).run()
+	if err != nil {
+		return errors.New("failed to set hook")
+	}
+
+	return nil
+}
+
+// Removes all the commands of a hook for the target.
+// The target is ignored for global hooks.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-hook
+func (t *Tmux) RemoveHook(target Target, name HookName, op *HookOptions) error {
+	return t.unsetHook(target, string(name), op)
+}
+
+// Unsets a hook or one of its indexes.
+func (t *Tmux) unsetHook(target Target, hook string, op *HookOptions) error {
+	q := t.query().
+		cmd("set-hook").
+		fargs("-u")
+	op.scopeArgs(q, target)
+
+	_, err := q.pargs(hook).run()
+	if err != nil {
+		return errors.New("failed to remove hook")
+	}
+
+	return nil
+}
+
+// Runs the commands of a hook immediately.
+// The target is ignored for global hooks.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-hook
+func (t *Tmux) RunHook(target Target, name HookName, op *HookOptions) error {
+	q := t.query().
+		cmd("set-hook").
+		fargs("-R")
+	op.scopeArgs(q, target)
+
+	_, err := q.pargs(string(name)).run()
+	if err != nil {
+		return errors.New("failed to run hook")
+	}
+
+	return nil
+}
+
+// Lists the hooks that are set for the target. Each command of a hook is returned separately.
+// The target is ignored for global hooks.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-hooks
+func (t *Tmux) ListHooks(target Target, op *HookOptions) ([]*Hook, error) {
+	q := t.query().
+		cmd("show-hooks")
+	op.scopeArgs(q, target)
+
+	o, err := q.run()
+	if err != nil {
+		return nil, errors.New("failed to list hooks")
+	}
+
+	scope := HookOptions{}
+	if op != nil {
+		scope = HookOptions{Global: op.Global, Window: op.Window, Pane: op.Pane}
+	}
+
+	out := make([]*Hook, 0)
+	for _, line := range strings.Split(o.raw(), "\n") {
+		h, ok := parseHook(line)
+		if !ok {
+			continue
+		}
+
+		h.target = target
+		h.scope = scope
+		h.tmux = t
+		out = append(out, h)
+	}
+
+	return out, nil
+}
+
+// Parses a line of show-hooks output, such as:
+//
+//	after-new-window[0] display-message "new window"
+//
+// Hooks that are not set are listed without index nor command and are skipped.
+func parseHook(line string) (*Hook, bool) {
+	name, command, ok := strings.Cut(line, " ")
+	if !ok {
+		return nil, false
+	}
+
+	open := strings.IndexByte(name, '[')
+	if open == -1 || !strings.HasSuffix(name, "]") {
+		return nil, false
+	}
+
+	idx, err := strconv.Atoi(name[open+1 : len(name)-1])
+	if err != nil {
+		return nil, false
+	}
+
+	h := &Hook{
+		Name:    HookName(name[:open]),
+		Index:   idx,
+		Command: command,
+	}
+
+	return h, true
+}
+
+// Removes this command from the hook.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-hook
+func (h *Hook) Remove() error {
+	err := h.tmux.unsetHook(h.target, fmt.Sprintf("%s[%d]", h.Name, h.Index), &h.scope)
+	if err != nil {
+		return err
+	}
+
+	if h.eventId != "" {
+		h.tmux.eventsMu.Lock()
+		l := h.tmux.events
+		h.tmux.eventsMu.Unlock()
+		if l != nil {
+			l.remove(h.eventId)
+		}
+	}
+
+	return nil
+}
+
+// Context in which a hook routed to Go was run.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#HOOKS
+type HookContext struct {
+	Hook        HookName
+	Client      string
+	SessionId   string
+	SessionName string
+	WindowId    string
+	WindowName  string
+	PaneId      string
+
+	tmux *Tmux
+}
+
+// Gets the client of the hook.
+func (c HookContext) GetClient() (*Client, error) {
+	return c.tmux.GetClientByName(c.Client)
+}
+
+// Gets the session of the hook.
+func (c HookContext) GetSession() (*Session, error) {
+	return c.tmux.GetSessionById(c.SessionId)
+}
+
+// Gets the window of the hook.
+func (c HookContext) GetWindow() (*Window, error) {
+	return c.tmux.GetWindowById(c.WindowId)
+}
+
+// Gets the pane of the hook.
+func (c HookContext) GetPane() (*Pane, error) {
+	return c.tmux.GetPaneById(c.PaneId)
+}
+
+// Counter used to identify hook handlers.
+var hookHandlerCount atomic.Uint64
+
+// Routes a hook to a Go handler, called with the hook variables when the hook runs.
+// The command is appended to the hook unless an index is set in the options.
+// Returns the hook command, which should be removed before exiting.
+// The target is ignored for global hooks.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#HOOKS
+func (t *Tmux) HandleHook(target Target, name HookName, op *HookOptions, handler func(ctx HookContext)) (*Hook, error) {
+	o := HookOptions{}
+	if op != nil {
+		o = *op
+	}
+
+	// The handler never replaces the other commands of the hook.
+	if o.Index == nil {
+		o.Append = true
+	}
+
+	l, err := t.eventListener()
+	if err != nil {
+		return nil, err
+	}
+
+	id := fmt.Sprintf("hook:%s:%d", name, hookHandlerCount.Add(1))
+	l.handle(id, func(values []string) {
+		if len(values) != 13 {
+			return
+		}
+
+		// Hooks run after commands do not set the hook variables,
+		// the variables of the target of the command are used instead.
+		value := func(hookIdx, idx int) string {
+			if values[hookIdx] != "" {
+				return values[hookIdx]
+			}
+			return values[idx]
+		}
+
+		handler(HookContext{
+			Hook:        HookName(values[0]),
+			Client:      value(1, 7),
+			SessionId:   value(2, 8),
+			SessionName: value(3, 9),
+			WindowId:    value(4, 10),
+			WindowName:  value(5, 11),
+			PaneId:      value(6, 12),
+
+			tmux: t,
+		})
+	})
+
+	command := l.command(id,
+		varHook,
+		varHookClient,
+		varHookSession,
+		varHookSessionName,
+		varHookWindow,
+		varHookWindowName,
+		varHookPane,
+		varClientName,
+		varSessionId,
+		varSessionName,
+		varWindowId,
+		varWindowName,
+		varPaneId,
+	)
+
+	if err := t.SetHook(target, name, command, &o); err != nil {
+		l.remove(id)
+		return nil, err
+	}
+
+	// Global window and pane hooks are set in the global window hooks by tmux.
+	scopes := []HookOptions{o}
+	if o.Global && !o.Window && !o.Pane {
+		scopes = append(scopes, HookOptions{Global: true, Window: true})
+	}
+
+	for _, scope := range scopes {
+		hooks, err := t.ListHooks(target, &scope)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, h := range hooks {
+			if h.Name == name && strings.Contains(h.Command, id) {
+				h.eventId = id
+				return h, nil
+			}
+		}
+	}
+
+	return nil, errors.New("failed to find hook")
+}
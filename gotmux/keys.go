@@ -0,0 +1,357 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Enumeration of the default key tables.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#KEY_BINDINGS
+const (
+	KeyTableRoot       = "root"
+	KeyTablePrefix     = "prefix"
+	KeyTableCopyMode   = "copy-mode"
+	KeyTableCopyModeVi = "copy-mode-vi"
+)
+
+// Tmux key binding object.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#KEY_BINDINGS
+type Binding struct {
+	// Key table of the binding. Defaults to the prefix table when binding.
+	Table string
+
+	// Key, such as 'C-a' or 'M-Left'.
+	Key string
+
+	// The key may repeat without pressing the prefix again.
+	Repeat bool
+
+	// Note describing the binding.
+	Note string
+
+	// Command to run, which may contain several commands separated by ';'.
+	Command string
+
+	tmux *Tmux
+}
+
+// Binds a key to a command.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#bind-key
+func (t *Tmux) BindKey(b Binding) error {
+	if b.Key == "" || b.Command == "" {
+		return errors.New("binding requires a key and a command")
+	}
+
+	q := t.query().
+		cmd("bind-key")
+
+	if b.Table != "" {
+		q.fargs("-T", b.Table)
+	}
+
+	if b.Repeat {
+		q.fargs("-r")
+	}
+
+	if b.Note != "" {
+		q.fargs("-N", b.Note)
+	}
+
+	_, err := q.pargs(b.Key, b.Command).run()
+	if err != nil {
+		return errors.New("failed to bind key")
+	}
+
+	return nil
+}
+
+// Unbinds a key. An empty table refers to the prefix table.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#unbind-key
+func (t *Tmux) UnbindKey(table, key string) error {
+	q := t.query().
+		cmd("unbind-key")
+
+	if table != "" {
+		q.fargs("-T", table)
+	}
+
+	_, err := q.pargs(key).run()
+	if err != nil {
+		return errors.New("failed to unbind key")
+	}
+
+	return nil
+}
+
+// Unbinds all the keys of a table. An empty table unbinds all the keys.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#unbind-key
+func (t *Tmux) UnbindAllKeys(table string) error {
+	q := t.query().
+		cmd("unbind-key").
+		fargs("-a")
+
+	if table != "" {
+		q.fargs("-T", table)
+	}
+
+	_, err := q.run()
+	if err != nil {
+		return errors.New("failed to unbind keys")
+	}
+
+	return nil
+}
+
+// Lists the key bindings of a table. An empty table lists the bindings of all tables.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#list-keys
+func (t *Tmux) ListKeys(table string) ([]*Binding, error) {
+	q := t.query().
+		cmd("list-keys")
+
+	if table != "" {
+		q.fargs("-T", table)
+	}
+
+	o, err := q.run()
+	if err != nil {
+		return nil, errors.New("failed to list keys")
+	}
+
+	out := make([]*Binding, 0)
+	notes := make(map[string]map[string]string)
+	for _, line := range strings.Split(o.raw(), "\n") {
+		b, ok := parseBinding(line)
+		if !ok {
+			continue
+		}
+
+		if _, ok := notes[b.Table]; !ok {
+			n, err := t.listKeyNotes(b.Table)
+			if err != nil {
+				return nil, err
+			}
+			notes[b.Table] = n
+		}
+
+		b.Note = notes[b.Table][b.Key]
+		b.tmux = t
+		out = append(out, b)
+	}
+
+	return out, nil
+}
+
+// Gets a key binding in a table. Returns nil if the key is not bound.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#list-keys
+func (t *Tmux) GetBinding(table, key string) (*Binding, error) {
+	bindings, err := t.ListKeys(table)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range bindings {
+		if b.Key == key {
+			return b, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Unbinds this key.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#unbind-key
+func (b *Binding) Unbind() error {
+	return b.tmux.UnbindKey(b.Table, b.Key)
+}
+
+// Returns the notes of the keys of a table by key.
+func (t *Tmux) listKeyNotes(table string) (map[string]string, error) {
+	o, err := t.query().
+		cmd("list-keys").
+		fargs("-N", "-P", "", "-T", table).
+		run()
+	if err != nil {
+		return nil, errors.New("failed to list key notes")
+	}
+
+	out := make(map[string]string)
+	for _, line := range strings.Split(o.raw(), "\n") {
+		key, note, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		out[key] = strings.TrimLeft(note, " ")
+	}
+
+	return out, nil
+}
+
+// Parses a line of list-keys output, such as:
+//
+//	bind-key -r -T prefix Up select-pane -U
+func parseBinding(line string) (*Binding, bool) {
+	rest, ok := strings.CutPrefix(line, "bind-key")
+	if !ok {
+		return nil, false
+	}
+
+	b := &Binding{}
+	for {
+		var token string
+		token, rest = nextKeyToken(rest)
+		switch token {
+		case "":
+			return nil, false
+		case "-r":
+			b.Repeat = true
+		case "-T":
+			b.Table, rest = nextKeyToken(rest)
+		default:
+			b.Key = unescapeKey(token)
+			b.Command = strings.TrimLeft(rest, " ")
+			return b, b.Table != ""
+		}
+	}
+}
+
+// Returns the next space separated token and the remainder.
+func nextKeyToken(s string) (string, string) {
+	s = strings.TrimLeft(s, " ")
+	idx := strings.IndexByte(s, ' ')
+	if idx == -1 {
+		return s, ""
+	}
+	return s[:idx], s[idx:]
+}
+
+// Removes the backslashes tmux uses to escape special keys.
+func unescapeKey(key string) string {
+	if len(key) == 2 && key[0] == '\\' {
+		return key[1:]
+	}
+	return key
+}
+
+// Context in which a key bound to a Go function was pressed.
+type KeyContext struct {
+	Table       string
+	Key         string
+	Client      string
+	SessionId   string
+	SessionName string
+	WindowId    string
+	WindowIndex int
+	PaneId      string
+
+	tmux *Tmux
+}
+
+// Gets the client that pressed the key.
+func (c KeyContext) GetClient() (*Client, error) {
+	return c.tmux.GetClientByName(c.Client)
+}
+
+// Gets the session in which the key was pressed.
+func (c KeyContext) GetSession() (*Session, error) {
+	return c.tmux.GetSessionById(c.SessionId)
+}
+
+// Gets the window in which the key was pressed.
+func (c KeyContext) GetWindow() (*Window, error) {
+	return c.tmux.GetWindowById(c.WindowId)
+}
+
+// Gets the pane in which the key was pressed.
+func (c KeyContext) GetPane() (*Pane, error) {
+	return c.tmux.GetPaneById(c.PaneId)
+}
+
+// Binds a key to a Go function, called with the context of the key press.
+// The function is called as long as the Tmux object is not closed,
+// the key should be unbound before exiting.
+// An empty table refers to the prefix table.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#bind-key
+func (t *Tmux) BindFunc(table, key string, handler func(ctx KeyContext)) error {
+	if table == "" {
+		table = KeyTablePrefix
+	}
+
+	l, err := t.eventListener()
+	if err != nil {
+		return err
+	}
+
+	id := fmt.Sprintf("key:%s:%s", table, key)
+	l.handle(id, func(values []string) {
+		if len(values) != 6 {
+			return
+		}
+
+		windowIndex, _ := strconv.Atoi(values[4])
+		handler(KeyContext{
+			Table:       table,
+			Key:         key,
+			Client:      values[0],
+			SessionId:   values[1],
+			SessionName: values[2],
+			WindowId:    values[3],
+			WindowIndex: windowIndex,
+			PaneId:      values[5],
+
+			tmux: t,
+		})
+	})
+
+	err = t.BindKey(Binding{
+		Table: table,
+		Key:   key,
+		Command: l.command(id,
+			varClientName,
+			varSessionId,
+			varSessionName,
+			varWindowId,
+			varWindowIndex,
+			varPaneId,
+		),
+	})
+	if err != nil {
+		l.remove(id)
+		return err
+	}
+
+	return nil
+}
+
+// Unbinds a key bound to a Go function.
+// An empty table refers to the prefix table.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#unbind-key
+func (t *Tmux) UnbindFunc(table, key string) error {
+	if table == "" {
+		table = KeyTablePrefix
+	}
+
+	t.eventsMu.Lock()
+	l := t.events
+	t.eventsMu.Unlock()
+	if l != nil {
+		l.remove(fmt.Sprintf("key:%s:%s", table, key))
+	}
+
+	return t.UnbindKey(table, key)
+}
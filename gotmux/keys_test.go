@@ -0,0 +1,80 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import "testing"
+
+// The lines are as printed by list-keys.
+func TestParseBinding(t *testing.T) {
+	tests := []struct {
+		line string
+		want Binding
+	}{
+		{
+			"bind-key    -T prefix       C-b                  send-prefix",
+			Binding{Table: "prefix", Key: "C-b", Command: "send-prefix"},
+		},
+		{
+			"bind-key -r -T prefix       M-Up                 resize-pane -U 5",
+			Binding{Table: "prefix", Key: "M-Up", Repeat: true, Command: "resize-pane -U 5"},
+		},
+		{
+			"bind-key -T copy-mode-vi C-c               send-keys -X cancel",
+			Binding{Table: "copy-mode-vi", Key: "C-c", Command: "send-keys -X cancel"},
+		},
+		{
+			`bind-key    -T prefix       &                    confirm-before -p "kill-window #W? (y/n)" kill-window`,
+			Binding{Table: "prefix", Key: "&", Command: `confirm-before -p "kill-window #W? (y/n)" kill-window`},
+		},
+		{
+			`bind-key    -T root         MouseDown1Pane       select-pane -t = \; send-keys -M`,
+			Binding{Table: "root", Key: "MouseDown1Pane", Command: `select-pane -t = \; send-keys -M`},
+		},
+		{
+			`bind-key    -T prefix \"      split-window`,
+			Binding{Table: "prefix", Key: `"`, Command: "split-window"},
+		},
+		{
+			`bind-key    -T prefix \;      last-pane`,
+			Binding{Table: "prefix", Key: ";", Command: "last-pane"},
+		},
+		{
+			`bind-key    -T prefix \{      swap-pane -U`,
+			Binding{Table: "prefix", Key: "{", Command: "swap-pane -U"},
+		},
+		{
+			`bind-key    -T prefix \\      display-message`,
+			Binding{Table: "prefix", Key: `\`, Command: "display-message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.Key, func(t *testing.T) {
+			got, ok := parseBinding(tt.line)
+			if !ok {
+				t.Fatalf("parseBinding(%q) failed", tt.line)
+			}
+			if *got != tt.want {
+				t.Errorf("parseBinding(%q) = %+v, want %+v", tt.line, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBindingInvalid(t *testing.T) {
+	lines := []string{
+		"",
+		"unbind-key -T prefix C-b",
+		"bind-key",
+		"bind-key -r -T prefix",
+		"bind-key C-b send-prefix",
+	}
+
+	for _, line := range lines {
+		if b, ok := parseBinding(line); ok {
+			t.Errorf("parseBinding(%q) = %+v, want failure", line, *b)
+		}
+	}
+}
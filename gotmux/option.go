@@ -1,9 +1,12 @@
 // Copyright (c) Gianluca Piccirillo
 // This software is licensed under the MIT License.
 // See the LICENSE file in the root directory for more information.
+
 package gotmux
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 )
 
@@ -13,22 +16,239 @@ import (
 type Option struct {
 	Key   string
 	Value string
+
+	// Whether the option is an element of an array option, at Index.
+	Array bool
+	Index int
+
+	// Whether the value is inherited from the parent scope rather than set at this scope.
+	Inherited bool
+}
+
+// Scope of an option.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+type OptionScope string
+
+// Enumeration of option scopes.
+// Global scopes refer to the values inherited by sessions and windows that do not set the option.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+const (
+	OptionScopeServer        OptionScope = "-s"
+	OptionScopeSession       OptionScope = ""
+	OptionScopeWindow        OptionScope = "-w"
+	OptionScopePane          OptionScope = "-p"
+	OptionScopeGlobalSession OptionScope = "-g"
+	OptionScopeGlobalWindow  OptionScope = "-gw"
+)
+
+// Returns true if the scope is global or server wide, in which case no target is needed.
+func (s OptionScope) IsGlobal() bool {
+	return s == OptionScopeServer || strings.HasPrefix(string(s), "-g")
+}
+
+// Appends the scope flag and the target to the query.
+func (s OptionScope) args(q *query, target Target) {
+	if s != "" {
+		q.fargs(string(s))
+	}
+
+	if target != "" || !s.IsGlobal() {
+		q.fargs("-t", target.arg())
+	}
 }
 
 func newOption(key, value string) *Option {
 	return &Option{Key: key, Value: value}
 }
 
+// Parses the output of show-options into options.
 func (q *queryOutput) toOptions() []*Option {
 	lines := strings.Split(q.raw(), "\n")
 	out := make([]*Option, 0)
 	for _, line := range lines {
-		s := strings.Split(line, " ")
-		if len(s) != 2 {
+		o, ok := parseOption(line)
+		if !ok {
 			continue
 		}
-		key, val := s[0], s[1]
-		out = append(out, newOption(key, val))
+		out = append(out, o)
 	}
 	return out
 }
+
+// Parses a line of show-options output, such as:
+//
+//	status-left "[#S] "
+//	terminal-features[0] xterm*:clipboard
+//	mouse* off
+//
+// Array options without elements are listed with no value and are skipped.
+func parseOption(line string) (*Option, bool) {
+	name, value, ok := strings.Cut(line, " ")
+	if !ok || name == "" {
+		return nil, false
+	}
+
+	o := &Option{}
+	if strings.HasSuffix(name, "*") {
+		o.Inherited = true
+		name = strings.TrimSuffix(name, "*")
+	}
+
+	if open := strings.IndexByte(name, '['); open != -1 && strings.HasSuffix(name, "]") {
+		idx, err := strconv.Atoi(name[open+1 : len(name)-1])
+		if err != nil {
+			return nil, false
+		}
+		o.Array = true
+		o.Index = idx
+		name = name[:open]
+	}
+
+	o.Key = name
+	o.Value = unquoteOptionValue(value)
+	return o, true
+}
+
+// Removes the quoting and escaping tmux applies to option values when showing them.
+// Values are wrapped in double or single quotes when they contain special characters,
+// and non printable characters are escaped in C style.
+func unquoteOptionValue(v string) string {
+	if len(v) >= 2 {
+		first, last := v[0], v[len(v)-1]
+		if (first == '"' || first == '\'') && last == first {
+			v = v[1 : len(v)-1]
+		}
+	}
+
+	if !strings.Contains(v, "\\") {
+		return v
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c != '\\' || i+1 >= len(v) {
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch v[i] {
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 's':
+			b.WriteByte(' ')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case '0', '1', '2', '3':
+			// Octal escape of up to three digits.
+			end := i + 1
+			for end < len(v) && end < i+3 && v[end] >= '0' && v[end] <= '7' {
+				end++
+			}
+			n, _ := strconv.ParseUint(v[i:end], 8, 8)
+			b.WriteByte(byte(n))
+			i = end - 1
+		default:
+			b.WriteByte(v[i])
+		}
+	}
+
+	return b.String()
+}
+
+// Retrieves the value of an option.
+// If inherited is true, the value inherited from the parent scope is returned when the option is not set.
+func (t *Tmux) optionValue(target Target, key string, level OptionScope, inherited bool) (string, error) {
+	q := t.query().
+		cmd("show-options")
+	level.args(q, target)
+
+	if inherited {
+		q.fargs("-A")
+	}
+
+	o, err := q.fargs("-v", key).run()
+	if err != nil {
+		return "", errors.New("failed to retrieve option")
+	}
+
+	return strings.TrimSuffix(o.raw(), "\n"), nil
+}
+
+// Retrieves the effective value of a flag option, including the value inherited from the parent scope.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (t *Tmux) OptionBool(target Target, key string, level OptionScope) (bool, error) {
+	v, err := t.optionValue(target, key, level, true)
+	if err != nil {
+		return false, err
+	}
+
+	switch v {
+	case "on", "1", "yes":
+		return true, nil
+	case "off", "0", "no", "":
+		return false, nil
+	default:
+		return false, errors.New("option is not a flag")
+	}
+}
+
+// Retrieves the effective value of a number option, including the value inherited from the parent scope.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (t *Tmux) OptionInt(target Target, key string, level OptionScope) (int, error) {
+	v, err := t.optionValue(target, key, level, true)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.New("option is not a number")
+	}
+
+	return n, nil
+}
+
+// Retrieves the effective value of a style option, including the value inherited from the parent scope.
+// Styles containing formats cannot be parsed and return an error.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#STYLES
+func (t *Tmux) OptionStyle(target Target, key string, level OptionScope) (*Style, error) {
+	v, err := t.optionValue(target, key, level, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseStyle(v)
+}
+
+// Retrieves the effective elements of an array option, including the elements inherited from the parent scope.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (t *Tmux) OptionArray(target Target, key string, level OptionScope) ([]string, error) {
+	v, err := t.optionValue(target, key, level, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == "" {
+		return []string{}, nil
+	}
+
+	return strings.Split(v, "\n"), nil
+}
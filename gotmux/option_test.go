@@ -0,0 +1,73 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import "testing"
+
+func TestParseOption(t *testing.T) {
+	tests := []struct {
+		line string
+		want *Option
+	}{
+		{"status on", &Option{Key: "status", Value: "on"}},
+		{"@plugin value with spaces", &Option{Key: "@plugin", Value: "value with spaces"}},
+		{"mode-keys* vi", &Option{Key: "mode-keys", Value: "vi", Inherited: true}},
+		{"status-format[1] \"#[align=centre]\"", &Option{Key: "status-format", Value: "#[align=centre]", Array: true, Index: 1}},
+		{"update-environment[3]* DISPLAY", &Option{Key: "update-environment", Value: "DISPLAY", Array: true, Index: 3, Inherited: true}},
+		{"@empty ''", &Option{Key: "@empty", Value: ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := parseOption(tt.line)
+			if !ok {
+				t.Fatalf("parseOption(%q) failed", tt.line)
+			}
+			if *got != *tt.want {
+				t.Errorf("parseOption(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOptionInvalid(t *testing.T) {
+	for _, line := range []string{"", "status", " on", "status-format[x] on"} {
+		if o, ok := parseOption(line); ok {
+			t.Errorf("parseOption(%q) = %+v, want failure", line, o)
+		}
+	}
+}
+
+// The quoted values are as shown by show-options for the unquoted values.
+func TestUnquoteOptionValue(t *testing.T) {
+	tests := []struct {
+		quoted string
+		want   string
+	}{
+		{"plain", "plain"},
+		{"''", ""},
+		{`"it's"`, "it's"},
+		{`"a \"b\" c"`, `a "b" c`},
+		{`"#{?x,\"y\",z}"`, `#{?x,"y",z}`},
+		{`back\\slash`, `back\slash`},
+		{`tab\there`, "tab\there"},
+		{`"é ü"`, "é ü"},
+		{`a\sb`, "a b"},
+		{`bell\007`, "bell\a"},
+		{`esc\033[0m`, "esc\x1b[0m"},
+		{`nul\0x`, "nul\x00x"},
+		{`trailing\`, `trailing\`},
+		{`"unbalanced`, `"unbalanced`},
+		{`"`, `"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.quoted, func(t *testing.T) {
+			if got := unquoteOptionValue(tt.quoted); got != tt.want {
+				t.Errorf("unquoteOptionValue(%q) = %q, want %q", tt.quoted, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,8 +6,11 @@ package gotmux
 
 import (
 	"errors"
-	"fmt"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GianlucaP106/gotmux/gotmux/screen"
 )
 
 // Tmux pane object.
@@ -26,7 +29,7 @@ type Pane struct {
 	Dead           bool
 	DeadSignal     int
 	DeadStatus     int
-	DeadTime       string
+	DeadTime       time.Time
 	Fg             string
 	Format         bool
 	Height         int
@@ -85,6 +88,33 @@ const (
 	PaneSplitDirectionVertical   PaneSplitDirection = "-v"
 )
 
+// Re-reads the fields of the pane from tmux, by pane id.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#display-message
+func (p *Pane) Refresh() error {
+	o, err := p.tmux.query().
+		cmd("display-message").
+		fargs("-t", p.Id).
+		paneVars().
+		run()
+	if err != nil {
+		return errors.New("failed to refresh pane")
+	}
+
+	fresh, err := o.one().toPane(p.tmux)
+	if err != nil {
+		return err
+	}
+
+	// Targets that do not exist are not an error for display-message, but expand to empty values.
+	if fresh.Id != p.Id {
+		return errors.New("pane not found")
+	}
+
+	*p = *fresh
+	return nil
+}
+
 // Pane send-keys.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#send-keys
@@ -92,7 +122,7 @@ func (p *Pane) SendKeys(line string) error {
 	_, err := p.tmux.query().
 		cmd("send-keys").
 		fargs("-t", p.Id).
-                pargs(line).
+		pargs(line).
 		run()
 	if err != nil {
 		return errors.New("failed to send keys")
@@ -142,7 +172,7 @@ func (p *Pane) SelectPane(op *SelectPaneOptions) error {
 		return errors.New("failed to select pane")
 	}
 
-	return nil
+	return p.Refresh()
 }
 
 // Selects the pane with the provided options.
@@ -159,7 +189,9 @@ func (p *Pane) Select() error {
 type SplitWindowOptions struct {
 	SplitDirection PaneSplitDirection
 	StartDirectory string
-	ShellCommand   string
+
+	// Command run by the shell in the new pane, passed as is.
+	ShellCommand string
 }
 
 // Split the window (pane).
@@ -180,7 +212,7 @@ func (p *Pane) SplitWindow(op *SplitWindowOptions) error {
 		}
 
 		if op.ShellCommand != "" {
-			q.pargs(fmt.Sprintf("'%s'", op.ShellCommand))
+			q.pargs(op.ShellCommand)
 		}
 	}
 
@@ -189,7 +221,7 @@ func (p *Pane) SplitWindow(op *SplitWindowOptions) error {
 		return errors.New("failed to split pane")
 	}
 
-	return nil
+	return p.Refresh()
 }
 
 // Split the window (pane).
@@ -234,6 +266,26 @@ func (p *Pane) ChooseTree(op *ChooseTreeOptions) error {
 	return nil
 }
 
+// Capture pane line position.
+// Either a line number, where 0 is the first line of the visible pane
+// and negative numbers are lines in the history, or 'CaptureLineEdge'.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+type CaptureLine string
+
+// Start of the history when used as a start line,
+// end of the visible pane when used as an end line.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+const CaptureLineEdge CaptureLine = "-"
+
+// Returns a capture line position for a line number.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+func CaptureLineAt(n int) CaptureLine {
+	return CaptureLine(strconv.Itoa(n))
+}
+
 // Capture pane command options.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
@@ -243,16 +295,39 @@ type CaptureOptions struct {
 	IgnoreTrailing   bool
 	PreserveTrailing bool
 	PreserveAndJoin  bool
+
+	// Captures the alternate screen instead of the normal screen.
+	AlternateScreen bool
+
+	// Does not fail if the alternate screen is requested but not in use.
+	Quiet bool
+
+	// Captures the output the pane has received but not yet processed.
+	PendingOutput bool
+
+	// First and last lines to capture. Defaults to the visible pane.
+	StartLine CaptureLine
+	EndLine   CaptureLine
+
+	// Captures into this buffer instead of returning the content.
+	Buffer string
 }
 
-// Captures the content of the pane
+// Captures the content of the pane.
+// If a buffer is provided in the options, the content is saved
+// in the buffer and the returned string is empty.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
 func (p *Pane) CapturePane(op *CaptureOptions) (string, error) {
 	q := p.tmux.query().
 		cmd("capture-pane").
-		fargs("-t", p.Id).
-		fargs("-p")
+		fargs("-t", p.Id)
+
+	if op != nil && op.Buffer != "" {
+		q.fargs("-b", op.Buffer)
+	} else {
+		q.fargs("-p")
+	}
 
 	if op != nil {
 		if op.EscTxtNBgAttr {
@@ -271,9 +346,29 @@ func (p *Pane) CapturePane(op *CaptureOptions) (string, error) {
 			q.fargs("-N")
 		}
 
-		if op.PreserveTrailing {
+		if op.PreserveAndJoin {
 			q.fargs("-J")
 		}
+
+		if op.AlternateScreen {
+			q.fargs("-a")
+		}
+
+		if op.Quiet {
+			q.fargs("-q")
+		}
+
+		if op.PendingOutput {
+			q.fargs("-P")
+		}
+
+		if op.StartLine != "" {
+			q.fargs("-S", string(op.StartLine))
+		}
+
+		if op.EndLine != "" {
+			q.fargs("-E", string(op.EndLine))
+		}
 	}
 
 	o, err := q.run()
@@ -284,6 +379,23 @@ func (p *Pane) CapturePane(op *CaptureOptions) (string, error) {
 	return o.result, nil
 }
 
+// Captures the content of the pane split into lines.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+func (p *Pane) CapturePaneLines(op *CaptureOptions) ([]string, error) {
+	content, err := p.CapturePane(op)
+	if err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return []string{}, nil
+	}
+
+	return strings.Split(content, "\n"), nil
+}
+
 // Captures the pane with background and text atrributes escaped.
 // Shorthand for `CapturePane`.
 //
@@ -294,33 +406,167 @@ func (p *Pane) Capture() (string, error) {
 	})
 }
 
+// Captures the full history and visible content of the pane as lines.
+// Shorthand for `CapturePaneLines`.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+func (p *Pane) CaptureHistory() ([]string, error) {
+	return p.CapturePaneLines(&CaptureOptions{
+		StartLine: CaptureLineEdge,
+		EndLine:   CaptureLineEdge,
+	})
+}
+
+// Captures the visible content of the pane as a screen of cells,
+// including colours, attributes and the cursor position.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+func (p *Pane) Screen() (*screen.Screen, error) {
+	c, err := p.captureScreen()
+	if err != nil {
+		return nil, err
+	}
+
+	s := screen.Parse(c.content, c.width, c.height)
+	s.Cursor = c.cursor
+	return s, nil
+}
+
+// Visible content of a pane with escapes, and the size and cursor at the time of the capture.
+type paneCapture struct {
+	content       string
+	width, height int
+	cursor        screen.Position
+}
+
+// Captures the visible content of the pane with its size and cursor.
+func (p *Pane) captureScreen() (*paneCapture, error) {
+	// The size and cursor are fetched in the same command sequence as the content, so they match.
+	o, err := p.tmux.query().
+		cmd("display-message").
+		fargs("-t", p.Id).
+		vars(varPaneWidth, varPaneHeight, varCursorX, varCursorY).
+		pargs(";", "capture-pane", "-p", "-e", "-t", p.Id).
+		run()
+	if err != nil {
+		return nil, errors.New("failed to capture pane")
+	}
+
+	header, content, ok := strings.Cut(o.result, "\n")
+	if !ok {
+		return nil, errors.New("failed to capture pane")
+	}
+
+	r := (&queryOutput{result: header, variables: o.variables}).one()
+	values := make([]int, 0, 4)
+	for _, v := range []string{varPaneWidth, varPaneHeight, varCursorX, varCursorY} {
+		n, err := strconv.Atoi(r.get(v))
+		if err != nil {
+			return nil, errors.New("failed to get pane size")
+		}
+		values = append(values, n)
+	}
+
+	return &paneCapture{
+		content: content,
+		width:   values[0],
+		height:  values[1],
+		cursor:  screen.Position{X: values[2], Y: values[3]},
+	}, nil
+}
+
+// Pipe pane options.
+// If neither Input nor Output is set, the output of the pane is piped.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#pipe-pane
+type PipePaneOptions struct {
+	// Sends the output of the command to the pane.
+	Input bool
+
+	// Sends the output of the pane to the command.
+	Output bool
+
+	// Only opens a pipe if there is none, allowing the pipe to be toggled.
+	Toggle bool
+}
+
+// Pipes the pane to or from a shell command.
+// An empty command closes the current pipe.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#pipe-pane
+func (p *Pane) PipePane(command string, op *PipePaneOptions) error {
+	q := p.tmux.query().
+		cmd("pipe-pane").
+		fargs("-t", p.Id)
+
+	if op != nil {
+		if op.Input {
+			q.fargs("-I")
+		}
+
+		if op.Output {
+			q.fargs("-O")
+		}
+
+		if op.Toggle {
+			q.fargs("-o")
+		}
+	}
+
+	if command != "" {
+		q.pargs(command)
+	}
+
+	_, err := q.run()
+	if err != nil {
+		return errors.New("failed to pipe pane")
+	}
+
+	return nil
+}
+
+// Closes the current pipe of the pane.
+// Shorthand for 'PipePane' with no command.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#pipe-pane
+func (p *Pane) StopPipe() error {
+	return p.PipePane("", nil)
+}
+
 // Sets an option with a given key.
 // Note that custom options must begin with '@'.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
 func (p *Pane) SetOption(key, option string) error {
-	return p.tmux.SetOption(p.Id, key, option, "-p")
+	return p.tmux.SetOption(p.Target(), key, option, OptionScopePane)
 }
 
 // Retrieves an option from this pane.
 //
 // https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
 func (p *Pane) Option(key string) (*Option, error) {
-	return p.tmux.Option(p.Id, key, "-p")
+	return p.tmux.Option(p.Target(), key, OptionScopePane)
 }
 
 // Retrieves all options in this pane.
 //
 // https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
 func (p *Pane) Options() ([]*Option, error) {
-	return p.tmux.Options(p.Id, "-p")
+	return p.tmux.Options(p.Target(), OptionScopePane)
+}
+
+// Lists the schemas of the built-in options that can be set on this pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (p *Pane) OptionSchemas() ([]*OptionSchema, error) {
+	return p.tmux.OptionSchemas(OptionScopePane)
 }
 
 // Deletes an option from this pane.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
 func (p *Pane) DeleteOption(key string) error {
-	return p.tmux.DeleteOption(p.Id, key, "-p")
+	return p.tmux.DeleteOption(p.Target(), key, OptionScopePane)
 }
 
 // Sets the pane variables in the query.
@@ -369,7 +615,7 @@ func (q *query) paneVars() *query {
 }
 
 // Converts a QueryResult to a pane.
-func (q queryResult) toPane(t *Tmux) *Pane {
+func (q queryResult) toPane(t *Tmux) (*Pane, error) {
 	active := isOne(q.get(varPaneActive))
 	atBottom := isOne(q.get(varPaneAtBottom))
 	atLeft := isOne(q.get(varPaneAtLeft))
@@ -382,7 +628,10 @@ func (q queryResult) toPane(t *Tmux) *Pane {
 	dead := isOne(q.get(varPaneDead))
 	deadSignal, _ := strconv.Atoi(q.get(varPaneDeadSignal))
 	deadStatus, _ := strconv.Atoi(q.get(varPaneDeadStatus))
-	deadTime := q.get(varPaneDeadTime)
+	deadTime, err := parseTime(q.get(varPaneDeadTime))
+	if err != nil {
+		return nil, err
+	}
 	fg := q.get(varPaneFg)
 	format := isOne(q.get(varPaneFormat))
 	height, _ := strconv.Atoi(q.get(varPaneHeight))
@@ -454,5 +703,5 @@ func (q queryResult) toPane(t *Tmux) *Pane {
 		tmux: t,
 	}
 
-	return p
+	return p, nil
 }
@@ -0,0 +1,222 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Stream between a pane and Go code, backed by pipe-pane and a FIFO.
+// The pipe ends when the pane dies, when the pipe is stopped or when it is closed.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#pipe-pane
+type Pipe struct {
+	pane   *Pane
+	dir    string
+	fifo   string
+	output bool
+
+	// Source of a pipe to the pane.
+	src io.Reader
+
+	mu     sync.Mutex
+	file   *os.File
+	closed bool
+
+	done chan struct{}
+	err  error
+}
+
+// Streams the output of the pane to the writer.
+// Replaces any existing pipe on the pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#pipe-pane
+func (p *Pane) PipeTo(w io.Writer) (*Pipe, error) {
+	pipe, err := newPipe(p, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.PipePane(fmt.Sprintf("cat > %s", shellQuote(pipe.fifo)), &PipePaneOptions{
+		Output: true,
+	})
+	if err != nil {
+		pipe.cleanup()
+		return nil, err
+	}
+
+	go pipe.run(func(f *os.File) error {
+		_, err := io.Copy(w, f)
+		return err
+	})
+
+	return pipe, nil
+}
+
+// Streams the reader to the pane as if it was typed.
+// Replaces any existing pipe on the pane.
+// Closing the pipe closes the reader if it is an io.Closer, otherwise Close does not wait
+// for a pending read of the reader to return, and Wait should be used instead.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#pipe-pane
+func (p *Pane) PipeFrom(r io.Reader) (*Pipe, error) {
+	pipe, err := newPipe(p, false)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe.src = r
+
+	err = p.PipePane(fmt.Sprintf("cat %s", shellQuote(pipe.fifo)), &PipePaneOptions{
+		Input: true,
+	})
+	if err != nil {
+		pipe.cleanup()
+		return nil, err
+	}
+
+	go pipe.run(func(f *os.File) error {
+		_, err := io.Copy(f, r)
+		return err
+	})
+
+	return pipe, nil
+}
+
+// Creates the FIFO backing a pipe.
+func newPipe(p *Pane, output bool) (*Pipe, error) {
+	dir, err := os.MkdirTemp("", "gotmux-pipe-")
+	if err != nil {
+		return nil, errors.New("failed to create pipe directory")
+	}
+
+	fifo := filepath.Join(dir, "fifo")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		os.RemoveAll(dir)
+		return nil, errors.New("failed to create pipe fifo")
+	}
+
+	return &Pipe{
+		pane:   p,
+		dir:    dir,
+		fifo:   fifo,
+		output: output,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Opens the FIFO and runs the copy until one side ends.
+func (p *Pipe) run(copy func(f *os.File) error) {
+	defer close(p.done)
+	defer p.cleanup()
+
+	flag := os.O_WRONLY
+	if p.output {
+		flag = os.O_RDONLY
+	}
+
+	// Blocks until the command started by tmux opens the other end.
+	f, err := os.OpenFile(p.fifo, flag, 0)
+	if err != nil {
+		p.err = errors.New("failed to open pipe")
+		return
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		f.Close()
+		return
+	}
+	p.file = f
+	p.mu.Unlock()
+
+	err = copy(f)
+	f.Close()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil && !p.closed && !errors.Is(err, syscall.EPIPE) {
+		p.err = err
+	}
+}
+
+// Removes the FIFO.
+func (p *Pipe) cleanup() {
+	os.RemoveAll(p.dir)
+}
+
+// Returns a channel that is closed when the pipe ends.
+func (p *Pipe) Done() <-chan struct{} {
+	return p.done
+}
+
+// Waits for the pipe to end.
+// Returns the error that ended the copy, if any.
+func (p *Pipe) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// Stops the pipe on the pane and waits for it to end.
+// See PipeFrom for pipes to the pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#pipe-pane
+func (p *Pipe) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return p.Wait()
+	}
+	p.closed = true
+	f := p.file
+	p.mu.Unlock()
+
+	// The pane may already be dead, in which case the pipe is already closed.
+	_ = p.pane.StopPipe()
+
+	if f == nil {
+		p.unblock()
+		return p.Wait()
+	}
+
+	f.Close()
+
+	// A pipe to the pane is blocked reading from its source rather than on the FIFO.
+	if !p.output {
+		c, ok := p.src.(io.Closer)
+		if !ok {
+			return nil
+		}
+		c.Close()
+	}
+
+	return p.Wait()
+}
+
+// Unblocks a pending open of the FIFO by opening the other end.
+func (p *Pipe) unblock() {
+	flag := os.O_RDONLY
+	if p.output {
+		flag = os.O_WRONLY
+	}
+
+	f, err := os.OpenFile(p.fifo, flag|syscall.O_NONBLOCK, 0)
+	if err == nil {
+		f.Close()
+	}
+}
+
+// Quotes a string for use as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -5,11 +5,13 @@
 package gotmux
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 )
 
@@ -23,6 +25,7 @@ type query struct {
 	pArgs     []string
 	command   []string
 	variables []string
+	in        io.Reader
 	out, err  io.Writer
 }
 
@@ -84,7 +87,7 @@ func (q *query) prepare() *exec.Cmd {
 	}()
 
 	if vars != "" {
-		if q.command[0] == "display-message" {
+		if slices.Contains(q.command, "display-message") {
 			query = append(query, "-p", vars)
 		} else {
 			query = append(query, "-F", vars)
@@ -97,7 +100,12 @@ func (q *query) prepare() *exec.Cmd {
 
 // Runs the query with output.
 func (q *query) run() (*queryOutput, error) {
-	b, err := q.prepare().Output()
+	cmd := q.prepare()
+	if q.in != nil {
+		cmd.Stdin = q.in
+	}
+
+	b, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +118,21 @@ func (q *query) run() (*queryOutput, error) {
 	return o, nil
 }
 
+// Runs the query, streaming the output to the writer.
+func (q *query) stream(w io.Writer) error {
+	cmd := q.prepare()
+	if q.in != nil {
+		cmd.Stdin = q.in
+	}
+	cmd.Stdout = w
+
+	return cmd.Run()
+}
+
+func (q *query) pipeIn(in io.Reader) {
+	q.in = in
+}
+
 func (q *query) pipeOut(out io.Writer) {
 	q.out = out
 }
@@ -146,8 +169,10 @@ func (q *queryOutput) collect() []queryResult {
 			continue
 		}
 
-		stripped := strings.Trim(line, "'")
-		vars := strings.Split(stripped, sep)
+		// Only the quotes wrapping the format are stripped, values may start or end with quotes.
+		stripped := strings.TrimPrefix(line, "'")
+		stripped = strings.TrimSuffix(stripped, "'")
+		vars := strings.SplitN(stripped, sep, len(q.variables))
 
 		if len(vars) != len(q.variables) {
 			log.Panicln("invalid query output")
@@ -164,6 +189,33 @@ func (q *queryOutput) collect() []queryResult {
 	return out
 }
 
+// Collects an output into a result like collect, but returns an error instead of panicking
+// when a line does not have exactly one value per variable, such as when a value contains the separator or a newline.
+func (q *queryOutput) collectStrict() ([]queryResult, error) {
+	out := make([]queryResult, 0)
+	for _, line := range strings.Split(q.result, "\n") {
+		if line == "" {
+			continue
+		}
+
+		stripped := strings.TrimPrefix(line, "'")
+		stripped = strings.TrimSuffix(stripped, "'")
+		vars := strings.Split(stripped, sep)
+		if len(vars) != len(q.variables) {
+			return nil, errors.New("invalid query output, a value contains a newline or the separator " + sep)
+		}
+
+		result := make(queryResult)
+		for idx, v := range q.variables {
+			result[v] = vars[idx]
+		}
+
+		out = append(out, result)
+	}
+
+	return out, nil
+}
+
 // Returns one element from the result.
 func (q *queryOutput) one() queryResult {
 	return q.collect()[0]
@@ -0,0 +1,59 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollectStrict(t *testing.T) {
+	vars := []string{"window_id", "window_name", ""}
+	tests := []struct {
+		name   string
+		result string
+		want   []queryResult
+	}{
+		{
+			"lines",
+			"'@0-:-bash-:-'\n'@1-:-vim-:-'\n",
+			[]queryResult{
+				{"window_id": "@0", "window_name": "bash", "": ""},
+				{"window_id": "@1", "window_name": "vim", "": ""},
+			},
+		},
+		{
+			"quotes in values",
+			"''@0-:-'a'-:-'\n",
+			[]queryResult{{"window_id": "'@0", "window_name": "'a'", "": ""}},
+		},
+		{"empty", "", []queryResult{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&queryOutput{result: tt.result, variables: vars}).collectStrict()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("collectStrict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectStrictInvalid(t *testing.T) {
+	vars := []string{"window_id", "window_name", ""}
+	for _, result := range []string{
+		"'@0-:-a-:-b-:-'\n",
+		"'@0-:-a\nb-:-'\n",
+		"'@0-:-a'\n",
+	} {
+		if got, err := (&queryOutput{result: result, variables: vars}).collectStrict(); err == nil {
+			t.Errorf("collectStrict(%q) = %v, want error", result, got)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Type of the value of a tmux option.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+type OptionType string
+
+// Enumeration of option types.
+const (
+	OptionTypeString  OptionType = "string"
+	OptionTypeNumber  OptionType = "number"
+	OptionTypeKey     OptionType = "key"
+	OptionTypeColour  OptionType = "colour"
+	OptionTypeFlag    OptionType = "flag"
+	OptionTypeChoice  OptionType = "choice"
+	OptionTypeCommand OptionType = "command"
+	OptionTypeStyle   OptionType = "style"
+)
+
+// Describes a built-in tmux option.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+type OptionSchema struct {
+	Name string
+
+	// One of OptionScopeServer, OptionScopeSession or OptionScopeWindow.
+	Scope OptionScope
+
+	// Whether the window option may also be set on individual panes.
+	Pane bool
+
+	Type OptionType
+
+	// Whether the option is an array option.
+	Array bool
+
+	// Allowed values of choice options.
+	Choices []string
+
+	// Bounds of number options. A zero Maximum means no upper bound.
+	Minimum int
+	Maximum int
+
+	// Default global value, as shown by show-options.
+	// Empty when the default depends on how tmux was built, such as for default-shell.
+	Default string
+
+	// First tmux version with the option, empty if it predates the versions gotmux supports.
+	MinVersion string
+}
+
+// Version of tmux the catalog was generated from.
+const optionCatalogVersion = "3.3a"
+
+// Built-in options of tmux 3.3a, as listed by show-options -A -g, -gs and -gw.
+var optionCatalog = []OptionSchema{
+	{Name: "backspace", Scope: OptionScopeServer, Type: OptionTypeKey, Default: "C-?"},
+	{Name: "buffer-limit", Scope: OptionScopeServer, Type: OptionTypeNumber, Minimum: 1, Default: "50"},
+	{Name: "command-alias", Scope: OptionScopeServer, Type: OptionTypeString, Array: true, MinVersion: "2.4"},
+	{Name: "copy-command", Scope: OptionScopeServer, Type: OptionTypeString, MinVersion: "3.2"},
+	{Name: "default-terminal", Scope: OptionScopeServer, Type: OptionTypeString},
+	{Name: "editor", Scope: OptionScopeServer, Type: OptionTypeString, MinVersion: "3.2"},
+	{Name: "escape-time", Scope: OptionScopeServer, Type: OptionTypeNumber, Default: "500"},
+	{Name: "exit-empty", Scope: OptionScopeServer, Type: OptionTypeFlag, Default: "on", MinVersion: "2.7"},
+	{Name: "exit-unattached", Scope: OptionScopeServer, Type: OptionTypeFlag, Default: "off"},
+	{Name: "extended-keys", Scope: OptionScopeServer, Type: OptionTypeChoice, Choices: []string{"off", "on", "always"}, Default: "off", MinVersion: "3.2"},
+	{Name: "focus-events", Scope: OptionScopeServer, Type: OptionTypeFlag, Default: "off"},
+	{Name: "history-file", Scope: OptionScopeServer, Type: OptionTypeString},
+	{Name: "message-limit", Scope: OptionScopeServer, Type: OptionTypeNumber, Default: "1000"},
+	{Name: "prompt-history-limit", Scope: OptionScopeServer, Type: OptionTypeNumber, Default: "100", MinVersion: "3.3"},
+	{Name: "set-clipboard", Scope: OptionScopeServer, Type: OptionTypeChoice, Choices: []string{"off", "external", "on"}, Default: "external"},
+	{Name: "terminal-features", Scope: OptionScopeServer, Type: OptionTypeString, Array: true, MinVersion: "3.2"},
+	{Name: "terminal-overrides", Scope: OptionScopeServer, Type: OptionTypeString, Array: true},
+	{Name: "user-keys", Scope: OptionScopeServer, Type: OptionTypeString, Array: true, MinVersion: "2.6"},
+	{Name: "activity-action", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"none", "any", "current", "other"}, Default: "other", MinVersion: "2.6"},
+	{Name: "assume-paste-time", Scope: OptionScopeSession, Type: OptionTypeNumber, Default: "1"},
+	{Name: "base-index", Scope: OptionScopeSession, Type: OptionTypeNumber, Default: "0"},
+	{Name: "bell-action", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"none", "any", "current", "other"}, Default: "any"},
+	{Name: "default-command", Scope: OptionScopeSession, Type: OptionTypeString},
+	{Name: "default-shell", Scope: OptionScopeSession, Type: OptionTypeString},
+	{Name: "default-size", Scope: OptionScopeSession, Type: OptionTypeString, Default: "80x24", MinVersion: "2.9"},
+	{Name: "destroy-unattached", Scope: OptionScopeSession, Type: OptionTypeFlag, Default: "off"},
+	{Name: "detach-on-destroy", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"off", "on", "no-detached"}, Default: "on"},
+	{Name: "display-panes-active-colour", Scope: OptionScopeSession, Type: OptionTypeColour, Default: "red"},
+	{Name: "display-panes-colour", Scope: OptionScopeSession, Type: OptionTypeColour, Default: "blue"},
+	{Name: "display-panes-time", Scope: OptionScopeSession, Type: OptionTypeNumber, Minimum: 1, Default: "1000"},
+	{Name: "display-time", Scope: OptionScopeSession, Type: OptionTypeNumber, Default: "750"},
+	{Name: "history-limit", Scope: OptionScopeSession, Type: OptionTypeNumber, Default: "2000"},
+	{Name: "key-table", Scope: OptionScopeSession, Type: OptionTypeString, Default: "root"},
+	{Name: "lock-after-time", Scope: OptionScopeSession, Type: OptionTypeNumber, Default: "0"},
+	{Name: "lock-command", Scope: OptionScopeSession, Type: OptionTypeString, Default: "lock -np"},
+	{Name: "message-command-style", Scope: OptionScopeSession, Type: OptionTypeStyle, Default: "bg=black,fg=yellow"},
+	{Name: "message-style", Scope: OptionScopeSession, Type: OptionTypeStyle, Default: "bg=yellow,fg=black"},
+	{Name: "mouse", Scope: OptionScopeSession, Type: OptionTypeFlag, Default: "off"},
+	{Name: "prefix", Scope: OptionScopeSession, Type: OptionTypeKey, Default: "C-b"},
+	{Name: "prefix2", Scope: OptionScopeSession, Type: OptionTypeKey, Default: "None"},
+	{Name: "renumber-windows", Scope: OptionScopeSession, Type: OptionTypeFlag, Default: "off"},
+	{Name: "repeat-time", Scope: OptionScopeSession, Type: OptionTypeNumber, Default: "500"},
+	{Name: "set-titles", Scope: OptionScopeSession, Type: OptionTypeFlag, Default: "off"},
+	{Name: "set-titles-string", Scope: OptionScopeSession, Type: OptionTypeString, Default: "#S:#I:#W - \"#T\" #{session_alerts}"},
+	{Name: "silence-action", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"none", "any", "current", "other"}, Default: "other", MinVersion: "2.6"},
+	{Name: "status", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"off", "on", "2", "3", "4", "5"}, Default: "on"},
+	{Name: "status-bg", Scope: OptionScopeSession, Type: OptionTypeColour, Default: "default"},
+	{Name: "status-fg", Scope: OptionScopeSession, Type: OptionTypeColour, Default: "default"},
+	{Name: "status-format", Scope: OptionScopeSession, Type: OptionTypeString, Array: true, MinVersion: "2.9"},
+	{Name: "status-interval", Scope: OptionScopeSession, Type: OptionTypeNumber, Default: "15"},
+	{Name: "status-justify", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"left", "centre", "right", "absolute-centre"}, Default: "left"},
+	{Name: "status-keys", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"emacs", "vi"}, Default: "emacs"},
+	{Name: "status-left", Scope: OptionScopeSession, Type: OptionTypeString, Default: "[#{session_name}] "},
+	{Name: "status-left-length", Scope: OptionScopeSession, Type: OptionTypeNumber, Maximum: 32767, Default: "10"},
+	{Name: "status-left-style", Scope: OptionScopeSession, Type: OptionTypeStyle, Default: "default"},
+	{Name: "status-position", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"top", "bottom"}, Default: "bottom"},
+	{Name: "status-right", Scope: OptionScopeSession, Type: OptionTypeString, Default: "#{?window_bigger,[#{window_offset_x}#,#{window_offset_y}] ,}\"#{=21:pane_title}\" %H:%M %d-%b-%y"},
+	{Name: "status-right-length", Scope: OptionScopeSession, Type: OptionTypeNumber, Maximum: 32767, Default: "40"},
+	{Name: "status-right-style", Scope: OptionScopeSession, Type: OptionTypeStyle, Default: "default"},
+	{Name: "status-style", Scope: OptionScopeSession, Type: OptionTypeStyle, Default: "bg=green,fg=black"},
+	{Name: "update-environment", Scope: OptionScopeSession, Type: OptionTypeString, Array: true},
+	{Name: "visual-activity", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"off", "on", "both"}, Default: "off"},
+	{Name: "visual-bell", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"off", "on", "both"}, Default: "off"},
+	{Name: "visual-silence", Scope: OptionScopeSession, Type: OptionTypeChoice, Choices: []string{"off", "on", "both"}, Default: "off"},
+	{Name: "word-separators", Scope: OptionScopeSession, Type: OptionTypeString, Default: "!\"#$%&'()*+,-./:;<=>?@[\\]^`{|}~"},
+	{Name: "aggressive-resize", Scope: OptionScopeWindow, Type: OptionTypeFlag, Default: "off"},
+	{Name: "allow-passthrough", Scope: OptionScopeWindow, Type: OptionTypeFlag, Pane: true, Default: "off", MinVersion: "3.3"},
+	{Name: "allow-rename", Scope: OptionScopeWindow, Type: OptionTypeFlag, Pane: true, Default: "off"},
+	{Name: "alternate-screen", Scope: OptionScopeWindow, Type: OptionTypeFlag, Pane: true, Default: "on"},
+	{Name: "automatic-rename", Scope: OptionScopeWindow, Type: OptionTypeFlag, Default: "on"},
+	{Name: "automatic-rename-format", Scope: OptionScopeWindow, Type: OptionTypeString, Default: "#{?pane_in_mode,[tmux],#{pane_current_command}}#{?pane_dead,[dead],}"},
+	{Name: "clock-mode-colour", Scope: OptionScopeWindow, Type: OptionTypeColour, Default: "blue"},
+	{Name: "clock-mode-style", Scope: OptionScopeWindow, Type: OptionTypeChoice, Choices: []string{"12", "24"}, Default: "24"},
+	{Name: "copy-mode-current-match-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "bg=magenta,fg=black", MinVersion: "3.2"},
+	{Name: "copy-mode-mark-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "bg=red,fg=black", MinVersion: "3.2"},
+	{Name: "copy-mode-match-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "bg=cyan,fg=black", MinVersion: "3.2"},
+	{Name: "cursor-colour", Scope: OptionScopeWindow, Type: OptionTypeColour, Pane: true, Default: "none", MinVersion: "3.3"},
+	{Name: "cursor-style", Scope: OptionScopeWindow, Type: OptionTypeChoice, Pane: true, Choices: []string{"default", "blinking-block", "block", "blinking-underline", "underline", "blinking-bar", "bar"}, Default: "default", MinVersion: "3.3"},
+	{Name: "fill-character", Scope: OptionScopeWindow, Type: OptionTypeString, MinVersion: "3.3"},
+	{Name: "main-pane-height", Scope: OptionScopeWindow, Type: OptionTypeNumber, Minimum: 1, Default: "24"},
+	{Name: "main-pane-width", Scope: OptionScopeWindow, Type: OptionTypeNumber, Minimum: 1, Default: "80"},
+	{Name: "mode-keys", Scope: OptionScopeWindow, Type: OptionTypeChoice, Choices: []string{"emacs", "vi"}, Default: "emacs"},
+	{Name: "mode-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "bg=yellow,fg=black"},
+	{Name: "monitor-activity", Scope: OptionScopeWindow, Type: OptionTypeFlag, Default: "off"},
+	{Name: "monitor-bell", Scope: OptionScopeWindow, Type: OptionTypeFlag, Default: "on", MinVersion: "2.6"},
+	{Name: "monitor-silence", Scope: OptionScopeWindow, Type: OptionTypeNumber, Default: "0"},
+	{Name: "other-pane-height", Scope: OptionScopeWindow, Type: OptionTypeNumber, Default: "0"},
+	{Name: "other-pane-width", Scope: OptionScopeWindow, Type: OptionTypeNumber, Default: "0"},
+	{Name: "pane-active-border-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "#{?pane_in_mode,fg=yellow,#{?synchronize-panes,fg=red,fg=green}}"},
+	{Name: "pane-base-index", Scope: OptionScopeWindow, Type: OptionTypeNumber, Maximum: 65535, Default: "0"},
+	{Name: "pane-border-format", Scope: OptionScopeWindow, Type: OptionTypeString, Default: "#{?pane_active,#[reverse],}#{pane_index}#[default] \"#{pane_title}\"", MinVersion: "2.3"},
+	{Name: "pane-border-indicators", Scope: OptionScopeWindow, Type: OptionTypeChoice, Choices: []string{"off", "colour", "arrows", "both"}, Default: "colour", MinVersion: "3.3"},
+	{Name: "pane-border-lines", Scope: OptionScopeWindow, Type: OptionTypeChoice, Choices: []string{"single", "double", "heavy", "simple", "number"}, Default: "single", MinVersion: "3.2"},
+	{Name: "pane-border-status", Scope: OptionScopeWindow, Type: OptionTypeChoice, Choices: []string{"off", "top", "bottom"}, Default: "off", MinVersion: "2.3"},
+	{Name: "pane-border-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "default"},
+	{Name: "pane-colours", Scope: OptionScopeWindow, Type: OptionTypeColour, Pane: true, Array: true, MinVersion: "3.3"},
+	{Name: "popup-border-lines", Scope: OptionScopeWindow, Type: OptionTypeChoice, Choices: []string{"single", "double", "heavy", "simple", "rounded", "padded", "none"}, Default: "single", MinVersion: "3.3"},
+	{Name: "popup-border-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "default", MinVersion: "3.3"},
+	{Name: "popup-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "default", MinVersion: "3.3"},
+	{Name: "remain-on-exit", Scope: OptionScopeWindow, Type: OptionTypeChoice, Pane: true, Choices: []string{"off", "on", "failed"}, Default: "off"},
+	{Name: "remain-on-exit-format", Scope: OptionScopeWindow, Type: OptionTypeString, Pane: true, Default: "Pane is dead (#{?#{!=:#{pane_dead_status},},status #{pane_dead_status},}#{?#{!=:#{pane_dead_signal},},signal #{pane_dead_signal},}, #{t:pane_dead_time})", MinVersion: "3.3"},
+	{Name: "scroll-on-clear", Scope: OptionScopeWindow, Type: OptionTypeFlag, Pane: true, Default: "on", MinVersion: "3.3"},
+	{Name: "synchronize-panes", Scope: OptionScopeWindow, Type: OptionTypeFlag, Pane: true, Default: "off"},
+	{Name: "window-active-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Pane: true, Default: "default", MinVersion: "2.1"},
+	{Name: "window-size", Scope: OptionScopeWindow, Type: OptionTypeChoice, Choices: []string{"largest", "smallest", "manual", "latest"}, Default: "latest", MinVersion: "2.9"},
+	{Name: "window-status-activity-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "reverse"},
+	{Name: "window-status-bell-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "reverse"},
+	{Name: "window-status-current-format", Scope: OptionScopeWindow, Type: OptionTypeString, Default: "#I:#W#{?window_flags,#{window_flags}, }"},
+	{Name: "window-status-current-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "default"},
+	{Name: "window-status-format", Scope: OptionScopeWindow, Type: OptionTypeString, Default: "#I:#W#{?window_flags,#{window_flags}, }"},
+	{Name: "window-status-last-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "default"},
+	{Name: "window-status-separator", Scope: OptionScopeWindow, Type: OptionTypeString, Default: " "},
+	{Name: "window-status-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Default: "default"},
+	{Name: "window-style", Scope: OptionScopeWindow, Type: OptionTypeStyle, Pane: true, Default: "default", MinVersion: "2.1"},
+	{Name: "wrap-search", Scope: OptionScopeWindow, Type: OptionTypeFlag, Default: "on"},
+	{Name: "xterm-keys", Scope: OptionScopeWindow, Type: OptionTypeFlag, Default: "on"},
+}
+
+// Finds the schema of a built-in option. Array indices such as "status-format[1]" are ignored.
+// The returned schema is a copy.
+func LookupOptionSchema(key string) (*OptionSchema, bool) {
+	if i := strings.IndexByte(key, '['); i != -1 {
+		key = key[:i]
+	}
+
+	for i := range optionCatalog {
+		if optionCatalog[i].Name == key {
+			return optionCatalog[i].clone(), true
+		}
+	}
+
+	return nil, false
+}
+
+// Lists the schemas of the built-in options that can be set at the given scope.
+// Pane scope includes only window options which may be set on panes.
+func OptionSchemas(scope OptionScope) []*OptionSchema {
+	out := make([]*OptionSchema, 0)
+	for i := range optionCatalog {
+		o := &optionCatalog[i]
+		if o.AppliesTo(scope) {
+			out = append(out, o.clone())
+		}
+	}
+
+	return out
+}
+
+// Returns a copy of the schema, so that the catalog cannot be modified by callers.
+func (o *OptionSchema) clone() *OptionSchema {
+	c := *o
+	c.Choices = append([]string(nil), o.Choices...)
+	return &c
+}
+
+// Returns true if the option can be set at the given scope.
+func (o *OptionSchema) AppliesTo(scope OptionScope) bool {
+	switch scope {
+	case OptionScopeServer:
+		return o.Scope == OptionScopeServer
+	case OptionScopeSession, OptionScopeGlobalSession:
+		return o.Scope == OptionScopeSession
+	case OptionScopeWindow, OptionScopeGlobalWindow:
+		return o.Scope == OptionScopeWindow
+	case OptionScopePane:
+		return o.Scope == OptionScopeWindow && o.Pane
+	}
+
+	return false
+}
+
+// Returns true if the option exists in the given tmux version.
+func (o *OptionSchema) Supports(version string) bool {
+	return o.MinVersion == "" || compareVersions(version, o.MinVersion) >= 0
+}
+
+// Checks that the value is valid for the option.
+// Only the shape of the value is checked, formats and styles are left to tmux.
+func (o *OptionSchema) Validate(value string) error {
+	switch o.Type {
+	case OptionTypeFlag:
+		switch value {
+		case "", "on", "off", "yes", "no", "1", "0":
+			return nil
+		}
+		return fmt.Errorf("invalid value %q for flag option %s", value, o.Name)
+	case OptionTypeChoice:
+		if !slices.Contains(o.Choices, value) {
+			return fmt.Errorf("invalid value %q for option %s, expected one of %s", value, o.Name, strings.Join(o.Choices, ", "))
+		}
+	case OptionTypeNumber:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for number option %s", value, o.Name)
+		}
+
+		max := o.Maximum
+		if max == 0 {
+			max = math.MaxInt32
+		}
+		if n < o.Minimum || n > max {
+			return fmt.Errorf("value %d for option %s out of range [%d, %d]", n, o.Name, o.Minimum, max)
+		}
+	case OptionTypeColour:
+		if !validColour(value) {
+			return fmt.Errorf("invalid colour %q for option %s", value, o.Name)
+		}
+	case OptionTypeKey:
+		if value == "" {
+			return fmt.Errorf("missing key for option %s", o.Name)
+		}
+	}
+
+	return nil
+}
+
+// Checks the shape of a colour. Named colours are not checked as tmux also accepts X11 colour names.
+func validColour(v string) bool {
+	if v == "" {
+		return false
+	}
+
+	lower := strings.ToLower(v)
+	for _, prefix := range []string{"colour", "color"} {
+		if n, ok := strings.CutPrefix(lower, prefix); ok {
+			i, err := strconv.Atoi(n)
+			if err == nil {
+				return i >= 0 && i <= 255
+			}
+		}
+	}
+
+	if strings.HasPrefix(v, "#") {
+		if len(v) != 7 {
+			return false
+		}
+		_, err := strconv.ParseUint(v[1:], 16, 32)
+		return err == nil
+	}
+
+	return true
+}
+
+// Validates a built-in option against the catalog and the version of the running server.
+// User options (starting with @) are always valid. On servers newer than the catalog,
+// unknown options and choices are left to tmux since they may have been added since.
+// Validation is not done by SetOption, it is up to the caller.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (t *Tmux) ValidateOption(key, value string, level OptionScope) error {
+	if strings.HasPrefix(key, "@") {
+		return nil
+	}
+
+	server, err := t.GetServerInformation()
+	if err != nil {
+		return errors.New("failed to retrieve server version")
+	}
+	newer := compareVersions(server.Version, optionCatalogVersion) > 0
+
+	schema, ok := LookupOptionSchema(key)
+	if !ok {
+		if newer {
+			return nil
+		}
+		return fmt.Errorf("unknown option %s", key)
+	}
+
+	if !schema.AppliesTo(level) {
+		return fmt.Errorf("option %s cannot be set at this scope", key)
+	}
+
+	if !schema.Supports(server.Version) {
+		return fmt.Errorf("option %s requires tmux %s", key, schema.MinVersion)
+	}
+
+	if newer && schema.Type == OptionTypeChoice {
+		return nil
+	}
+
+	return schema.Validate(value)
+}
+
+// Lists the schemas of the options available at the given scope on the running server, as listed by show-options -A -g.
+// Options missing from the catalog are described as string options with their current global value as default.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (t *Tmux) OptionSchemas(level OptionScope) ([]*OptionSchema, error) {
+	global := OptionScopeGlobalSession
+	switch level {
+	case OptionScopeServer:
+		global = OptionScopeServer
+	case OptionScopeWindow, OptionScopeGlobalWindow, OptionScopePane:
+		global = OptionScopeGlobalWindow
+	}
+
+	options, err := t.EffectiveOptions("", global)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*OptionSchema, 0)
+	seen := make(map[string]bool)
+	for _, o := range options {
+		name := o.Key
+		if i := strings.IndexByte(name, '['); i != -1 {
+			name = name[:i]
+		}
+
+		if strings.HasPrefix(name, "@") || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		schema, ok := LookupOptionSchema(name)
+		if !ok {
+			// Pane options cannot be told apart from window options without the catalog.
+			if level == OptionScopePane {
+				continue
+			}
+
+			schema = &OptionSchema{
+				Name:    name,
+				Scope:   global,
+				Type:    OptionTypeString,
+				Array:   o.Array,
+				Default: o.Value,
+			}
+			if global == OptionScopeGlobalSession {
+				schema.Scope = OptionScopeSession
+			} else if global == OptionScopeGlobalWindow {
+				schema.Scope = OptionScopeWindow
+			}
+		} else if !schema.AppliesTo(level) {
+			continue
+		}
+
+		out = append(out, schema)
+	}
+
+	return out, nil
+}
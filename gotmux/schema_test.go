@@ -0,0 +1,58 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import "testing"
+
+// Schemas returned to callers are copies of the catalog.
+func TestOptionSchemaCopies(t *testing.T) {
+	s, ok := LookupOptionSchema("status-position")
+	if !ok {
+		t.Fatal("status-position not found")
+	}
+	s.Default = "changed"
+	s.Choices[0] = "changed"
+
+	for _, s := range OptionSchemas(OptionScopeSession) {
+		if s.Name == "status-position" {
+			s.Type = OptionTypeNumber
+		}
+	}
+
+	again, _ := LookupOptionSchema("status-position")
+	if again.Default == "changed" || again.Choices[0] == "changed" || again.Type != OptionTypeChoice {
+		t.Errorf("catalog was modified: %+v", again)
+	}
+}
+
+func TestOptionSchemaValidate(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		ok    bool
+	}{
+		{"status-position", "top", true},
+		{"status-position", "middle", false},
+		{"history-limit", "5000", true},
+		{"history-limit", "-1", false},
+		{"history-limit", "many", false},
+		{"mouse", "on", true},
+		{"mouse", "yes", true},
+		{"mouse", "maybe", false},
+		{"status-style", "fg=red,bold", true},
+	}
+
+	for _, tt := range tests {
+		s, ok := LookupOptionSchema(tt.key)
+		if !ok {
+			t.Fatalf("%s not found", tt.key)
+		}
+
+		err := s.Validate(tt.value)
+		if (err == nil) != tt.ok {
+			t.Errorf("Validate(%s, %q) = %v, want ok %v", tt.key, tt.value, err, tt.ok)
+		}
+	}
+}
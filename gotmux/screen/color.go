@@ -0,0 +1,110 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package screen
+
+import "fmt"
+
+// Kind of a cell colour.
+type ColorKind int
+
+// Enumeration of colour kinds.
+const (
+	// The terminal default colour.
+	ColorDefault ColorKind = iota
+
+	// One of the 256 indexed colours. The first 16 are the named colours.
+	ColorIndexed
+
+	// A 24-bit RGB colour.
+	ColorRGB
+)
+
+// Cell foreground or background colour.
+type Color struct {
+	Kind    ColorKind
+	Index   uint8
+	R, G, B uint8
+}
+
+// The terminal default colour.
+var DefaultColor = Color{Kind: ColorDefault}
+
+// Returns an indexed colour.
+func IndexedColor(idx uint8) Color {
+	return Color{Kind: ColorIndexed, Index: idx}
+}
+
+// Returns an RGB colour.
+func RGBColor(r, g, b uint8) Color {
+	return Color{Kind: ColorRGB, R: r, G: g, B: b}
+}
+
+// Returns true if this is the terminal default colour.
+func (c Color) IsDefault() bool {
+	return c.Kind == ColorDefault
+}
+
+// Returns the RGB value of the colour using the standard xterm palette.
+// The default colour resolves to the provided fallback.
+func (c Color) RGB(fallback Color) (uint8, uint8, uint8) {
+	switch c.Kind {
+	case ColorRGB:
+		return c.R, c.G, c.B
+	case ColorIndexed:
+		return paletteRGB(c.Index)
+	default:
+		if fallback.Kind == ColorDefault {
+			return 0, 0, 0
+		}
+		return fallback.RGB(DefaultColor)
+	}
+}
+
+// Returns the colour as a hex string (#rrggbb) using the standard xterm palette.
+func (c Color) Hex(fallback Color) string {
+	r, g, b := c.RGB(fallback)
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// The 16 named colours of the xterm palette.
+var namedPalette = [16][3]uint8{
+	{0x00, 0x00, 0x00},
+	{0xcd, 0x00, 0x00},
+	{0x00, 0xcd, 0x00},
+	{0xcd, 0xcd, 0x00},
+	{0x00, 0x00, 0xee},
+	{0xcd, 0x00, 0xcd},
+	{0x00, 0xcd, 0xcd},
+	{0xe5, 0xe5, 0xe5},
+	{0x7f, 0x7f, 0x7f},
+	{0xff, 0x00, 0x00},
+	{0x00, 0xff, 0x00},
+	{0xff, 0xff, 0x00},
+	{0x5c, 0x5c, 0xff},
+	{0xff, 0x00, 0xff},
+	{0x00, 0xff, 0xff},
+	{0xff, 0xff, 0xff},
+}
+
+// Resolves an indexed colour to RGB.
+func paletteRGB(idx uint8) (uint8, uint8, uint8) {
+	switch {
+	case idx < 16:
+		c := namedPalette[idx]
+		return c[0], c[1], c[2]
+	case idx < 232:
+		i := idx - 16
+		level := func(v uint8) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return level(i / 36), level((i / 6) % 6), level(i % 6)
+	default:
+		v := 8 + (idx-232)*10
+		return v, v, v
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package screen
+
+// A screen placed at a position of a larger screen, such as a pane in a window.
+type Region struct {
+	X      int
+	Y      int
+	Screen *Screen
+	Active bool
+}
+
+// Colour used for the borders of the active region, like tmux's default pane-active-border-style.
+var activeBorderColor = IndexedColor(2)
+
+// Composes regions into a single screen of the given size.
+// Cells not covered by a region are drawn as borders between the regions,
+// the borders around the active region are highlighted.
+// The cursor is placed at the cursor of the active region.
+func Compose(width, height int, regions []Region) *Screen {
+	s := New(width, height)
+	covered := make([][]bool, height)
+	for y := range covered {
+		covered[y] = make([]bool, width)
+	}
+
+	for _, r := range regions {
+		for y := 0; y < r.Screen.Height; y++ {
+			for x := 0; x < r.Screen.Width; x++ {
+				if !s.inBounds(r.X+x, r.Y+y) {
+					continue
+				}
+				s.Cells[r.Y+y][r.X+x] = r.Screen.Cells[y][x]
+				covered[r.Y+y][r.X+x] = true
+			}
+		}
+
+		if r.Active {
+			s.Cursor = Position{X: r.X + r.Screen.Cursor.X, Y: r.Y + r.Screen.Cursor.Y}
+		}
+	}
+
+	border := func(x, y int) bool {
+		return s.inBounds(x, y) && !covered[y][x]
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if covered[y][x] {
+				continue
+			}
+
+			cell := Cell{Rune: borderRune(border(x, y-1), border(x, y+1), border(x-1, y), border(x+1, y))}
+			if adjacentToActive(x, y, regions) {
+				cell.Fg = activeBorderColor
+			}
+			s.Cells[y][x] = cell
+		}
+	}
+
+	return s
+}
+
+// Returns the box drawing character joining the neighbouring borders.
+func borderRune(up, down, left, right bool) rune {
+	vertical := up || down
+	horizontal := left || right
+	switch {
+	case up && down && left && right:
+		return '┼'
+	case up && down && right:
+		return '├'
+	case up && down && left:
+		return '┤'
+	case left && right && down:
+		return '┬'
+	case left && right && up:
+		return '┴'
+	case down && right:
+		return '┌'
+	case down && left:
+		return '┐'
+	case up && right:
+		return '└'
+	case up && left:
+		return '┘'
+	case vertical:
+		return '│'
+	case horizontal:
+		return '─'
+	default:
+		return ' '
+	}
+}
+
+// Returns true if the cell touches the active region, including diagonally.
+func adjacentToActive(x, y int, regions []Region) bool {
+	for _, r := range regions {
+		if !r.Active {
+			continue
+		}
+		if x >= r.X-1 && x <= r.X+r.Screen.Width && y >= r.Y-1 && y <= r.Y+r.Screen.Height {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package screen
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Parses the output of capture-pane with escape sequences into a screen of the given size.
+// Lines and columns beyond the size are dropped.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+func Parse(content string, width, height int) *Screen {
+	s := New(width, height)
+	p := &parser{screen: s, pen: Cell{Rune: ' '}}
+	p.parse(content)
+	return s
+}
+
+// Escape sequence parser state.
+type parser struct {
+	screen *Screen
+	pen    Cell
+	x, y   int
+}
+
+// Parses the content onto the screen.
+func (p *parser) parse(content string) {
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch c {
+		case '\x1b':
+			i = p.escape(content, i)
+			continue
+		case '\n':
+			p.x = 0
+			p.y++
+		case '\r':
+			p.x = 0
+		case '\t':
+			p.x = (p.x/8 + 1) * 8
+		default:
+			if c < 0x20 || c == 0x7f {
+				break
+			}
+			r, size := utf8.DecodeRuneInString(content[i:])
+			p.put(r)
+			i += size
+			continue
+		}
+		i++
+	}
+}
+
+// Puts a rune at the current position and advances.
+func (p *parser) put(r rune) {
+	cell := p.pen
+	cell.Rune = r
+	p.screen.SetCell(p.x, p.y, cell)
+	p.x++
+
+	if isWide(r) {
+		pad := p.pen
+		pad.Rune = 0
+		pad.Padding = true
+		p.screen.SetCell(p.x, p.y, pad)
+		p.x++
+	}
+}
+
+// Handles an escape sequence starting at i. Returns the index after it.
+func (p *parser) escape(content string, i int) int {
+	if i+1 >= len(content) {
+		return len(content)
+	}
+
+	switch content[i+1] {
+	case '[':
+		end := i + 2
+		for end < len(content) && (content[end] < 0x40 || content[end] > 0x7e) {
+			end++
+		}
+		if end >= len(content) {
+			return len(content)
+		}
+		if content[end] == 'm' {
+			p.sgr(content[i+2 : end])
+		}
+		return end + 1
+	case ']':
+		body, next := oscBody(content, i+2)
+		p.osc(body)
+		return next
+	default:
+		return i + 2
+	}
+}
+
+// Returns the body of an OSC sequence and the index after its terminator.
+// The sequence is terminated by BEL or ST (ESC \).
+func oscBody(content string, start int) (string, int) {
+	for j := start; j < len(content); j++ {
+		if content[j] == '\a' {
+			return content[start:j], j + 1
+		}
+		if content[j] == '\x1b' && j+1 < len(content) && content[j+1] == '\\' {
+			return content[start:j], j + 2
+		}
+	}
+	return content[start:], len(content)
+}
+
+// Handles an OSC sequence. Only hyperlinks (OSC 8) are supported.
+func (p *parser) osc(body string) {
+	parts := strings.SplitN(body, ";", 3)
+	if len(parts) != 3 || parts[0] != "8" {
+		return
+	}
+	p.pen.Hyperlink = parts[2]
+}
+
+// Handles a select graphic rendition sequence.
+func (p *parser) sgr(params string) {
+	if params == "" {
+		params = "0"
+	}
+
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+
+		// Colon separated sub-parameters, used for underline styles and colours.
+		sub := strings.Split(f, ":")
+		n, err := strconv.Atoi(sub[0])
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case n == 0:
+			link := p.pen.Hyperlink
+			p.pen = Cell{Rune: ' ', Hyperlink: link}
+		case n == 1:
+			p.pen.Attrs |= AttrBold
+		case n == 2:
+			p.pen.Attrs |= AttrDim
+		case n == 3:
+			p.pen.Attrs |= AttrItalic
+		case n == 4:
+			p.pen.Attrs &^= attrUnderlines
+			p.pen.Attrs |= underlineStyle(sub)
+		case n == 5 || n == 6:
+			p.pen.Attrs |= AttrBlink
+		case n == 7:
+			p.pen.Attrs |= AttrReverse
+		case n == 8:
+			p.pen.Attrs |= AttrHidden
+		case n == 9:
+			p.pen.Attrs |= AttrStrikethrough
+		case n == 21:
+			p.pen.Attrs &^= attrUnderlines
+			p.pen.Attrs |= AttrDoubleUnderline
+		case n == 22:
+			p.pen.Attrs &^= AttrBold | AttrDim
+		case n == 23:
+			p.pen.Attrs &^= AttrItalic
+		case n == 24:
+			p.pen.Attrs &^= attrUnderlines
+		case n == 25:
+			p.pen.Attrs &^= AttrBlink
+		case n == 27:
+			p.pen.Attrs &^= AttrReverse
+		case n == 28:
+			p.pen.Attrs &^= AttrHidden
+		case n == 29:
+			p.pen.Attrs &^= AttrStrikethrough
+		case n >= 30 && n <= 37:
+			p.pen.Fg = IndexedColor(uint8(n - 30))
+		case n == 38:
+			c, used := extendedColor(sub, fields[i+1:])
+			p.pen.Fg = c
+			i += used
+		case n == 39:
+			p.pen.Fg = DefaultColor
+		case n >= 40 && n <= 47:
+			p.pen.Bg = IndexedColor(uint8(n - 40))
+		case n == 48:
+			c, used := extendedColor(sub, fields[i+1:])
+			p.pen.Bg = c
+			i += used
+		case n == 49:
+			p.pen.Bg = DefaultColor
+		case n == 53:
+			p.pen.Attrs |= AttrOverline
+		case n == 55:
+			p.pen.Attrs &^= AttrOverline
+		case n >= 90 && n <= 97:
+			p.pen.Fg = IndexedColor(uint8(n - 90 + 8))
+		case n >= 100 && n <= 107:
+			p.pen.Bg = IndexedColor(uint8(n - 100 + 8))
+		}
+	}
+}
+
+// Returns the underline attribute for the sub-parameters of SGR 4.
+func underlineStyle(sub []string) Attr {
+	if len(sub) < 2 {
+		return AttrUnderline
+	}
+
+	switch sub[1] {
+	case "0":
+		return 0
+	case "2":
+		return AttrDoubleUnderline
+	case "3":
+		return AttrCurlyUnderline
+	case "4":
+		return AttrDottedUnderline
+	case "5":
+		return AttrDashedUnderline
+	default:
+		return AttrUnderline
+	}
+}
+
+// Parses an extended colour (SGR 38 and 48).
+// Supports both the colon form (38:5:n) and the semicolon form (38;5;n).
+// Returns the colour and the number of following fields consumed.
+func extendedColor(sub []string, rest []string) (Color, int) {
+	args := sub[1:]
+	used := 0
+	if len(args) == 0 {
+		args = rest
+	}
+
+	atoi := func(idx int) (uint8, bool) {
+		if idx >= len(args) {
+			return 0, false
+		}
+		n, err := strconv.Atoi(args[idx])
+		if err != nil || n < 0 || n > 255 {
+			return 0, false
+		}
+		return uint8(n), true
+	}
+
+	if len(args) == 0 {
+		return DefaultColor, 0
+	}
+
+	switch args[0] {
+	case "5":
+		idx, ok := atoi(1)
+		if len(sub) == 1 {
+			used = min(2, len(args))
+		}
+		if !ok {
+			return DefaultColor, used
+		}
+		return IndexedColor(idx), used
+	case "2":
+		// The colon form may include an empty colour space identifier.
+		off := 1
+		if len(sub) > 1 && len(args) == 5 {
+			off = 2
+		}
+		r, okR := atoi(off)
+		g, okG := atoi(off + 1)
+		b, okB := atoi(off + 2)
+		if len(sub) == 1 {
+			used = min(4, len(args))
+		}
+		if !okR || !okG || !okB {
+			return DefaultColor, used
+		}
+		return RGBColor(r, g, b), used
+	default:
+		if len(sub) == 1 {
+			used = 1
+		}
+		return DefaultColor, used
+	}
+}
+
+// Returns true if the rune occupies two cells.
+func isWide(r rune) bool {
+	switch {
+	case r < 0x1100:
+		return false
+	case r <= 0x115f,
+		r == 0x2329, r == 0x232a,
+		r >= 0x2e80 && r <= 0x303e,
+		r >= 0x3041 && r <= 0x33ff,
+		r >= 0x3400 && r <= 0x4dbf,
+		r >= 0x4e00 && r <= 0x9fff,
+		r >= 0xa000 && r <= 0xa4cf,
+		r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff,
+		r >= 0xfe30 && r <= 0xfe4f,
+		r >= 0xff00 && r <= 0xff60,
+		r >= 0xffe0 && r <= 0xffe6,
+		r >= 0x1f300 && r <= 0x1f64f,
+		r >= 0x1f900 && r <= 0x1f9ff,
+		r >= 0x20000 && r <= 0x3fffd:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package screen
+
+import "testing"
+
+func TestParseText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		width   int
+		height  int
+		want    []string
+	}{
+		{"plain", "ab\ncd", 4, 2, []string{"ab", "cd"}},
+		{"truncated", "abcdef\n1\n2", 3, 2, []string{"abc", "1"}},
+		{"carriage return", "abc\rX", 4, 1, []string{"Xbc"}},
+		{"tab", "a\tb", 10, 1, []string{"a       b"}},
+		{"control dropped", "a\x07b", 4, 1, []string{"ab"}},
+		{"csi dropped", "a\x1b[2Kb", 4, 1, []string{"ab"}},
+		{"wide", "世a", 4, 1, []string{"世a"}},
+		{"unterminated escape", "ab\x1b[3", 4, 1, []string{"ab"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Parse(tt.content, tt.width, tt.height)
+			if s.Width != tt.width || s.Height != tt.height {
+				t.Fatalf("size = %dx%d, want %dx%d", s.Width, s.Height, tt.width, tt.height)
+			}
+			got := s.Lines()
+			for i, want := range tt.want {
+				if got[i] != want {
+					t.Errorf("line %d = %q, want %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseWidePadding(t *testing.T) {
+	s := Parse("世", 4, 1)
+	if s.Cells[0][0].Rune != '世' || s.Cells[0][0].Padding {
+		t.Errorf("cell 0 = %+v, want the wide rune", s.Cells[0][0])
+	}
+	if !s.Cells[0][1].Padding {
+		t.Errorf("cell 1 = %+v, want a padding cell", s.Cells[0][1])
+	}
+}
+
+func TestParseSGR(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Cell
+	}{
+		{"default", "x", Cell{Rune: 'x'}},
+		{"bold italic", "\x1b[1;3mx", Cell{Rune: 'x', Attrs: AttrBold | AttrItalic}},
+		{"reset", "\x1b[1;31m\x1b[mx", Cell{Rune: 'x'}},
+		{"normal intensity", "\x1b[1;2;22mx", Cell{Rune: 'x'}},
+		{"indexed fg", "\x1b[31mx", Cell{Rune: 'x', Fg: IndexedColor(1)}},
+		{"bright bg", "\x1b[101mx", Cell{Rune: 'x', Bg: IndexedColor(9)}},
+		{"256 semicolon", "\x1b[38;5;200mx", Cell{Rune: 'x', Fg: IndexedColor(200)}},
+		{"256 colon", "\x1b[48:5:17mx", Cell{Rune: 'x', Bg: IndexedColor(17)}},
+		{"rgb semicolon", "\x1b[38;2;1;2;3mx", Cell{Rune: 'x', Fg: RGBColor(1, 2, 3)}},
+		{"rgb colon", "\x1b[38:2:1:2:3mx", Cell{Rune: 'x', Fg: RGBColor(1, 2, 3)}},
+		{"rgb colon colour space", "\x1b[38:2::1:2:3mx", Cell{Rune: 'x', Fg: RGBColor(1, 2, 3)}},
+		{"extended then attr", "\x1b[38;5;1;1mx", Cell{Rune: 'x', Fg: IndexedColor(1), Attrs: AttrBold}},
+		{"default fg", "\x1b[31;39mx", Cell{Rune: 'x'}},
+		{"underline", "\x1b[4mx", Cell{Rune: 'x', Attrs: AttrUnderline}},
+		{"curly underline", "\x1b[4:3mx", Cell{Rune: 'x', Attrs: AttrCurlyUnderline}},
+		{"double underline", "\x1b[4;21mx", Cell{Rune: 'x', Attrs: AttrDoubleUnderline}},
+		{"underline off", "\x1b[4;24mx", Cell{Rune: 'x'}},
+		{"overline", "\x1b[53mx", Cell{Rune: 'x', Attrs: AttrOverline}},
+		{"hyperlink", "\x1b]8;;https://example.com\x1b\\x", Cell{Rune: 'x', Hyperlink: "https://example.com"}},
+		{"hyperlink bel", "\x1b]8;id=1;https://example.com\ax", Cell{Rune: 'x', Hyperlink: "https://example.com"}},
+		{"hyperlink survives reset", "\x1b]8;;a\a\x1b[0mx", Cell{Rune: 'x', Hyperlink: "a"}},
+		{"hyperlink end", "\x1b]8;;a\a\x1b]8;;\ax", Cell{Rune: 'x'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.content, 2, 1).Cells[0][0]
+			if got != tt.want {
+				t.Errorf("cell = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package screen
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Returns true if the hyperlink may be rendered as a link.
+// Only web and file links are allowed, so that links such as javascript: in the pane output are rendered as plain text.
+func safeLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "file":
+		return true
+	}
+
+	return false
+}
+
+// Options for rendering a screen to HTML or SVG.
+type RenderOptions struct {
+	// Title of the document.
+	Title string
+
+	// CSS font family. Defaults to a monospace font.
+	// Characters other than letters, digits, spaces, quotes and , - _ . are removed.
+	FontFamily string
+
+	// Font size in pixels. Defaults to 14.
+	FontSize int
+
+	// Colours used for the default foreground and background.
+	// Default to light grey on black.
+	Foreground Color
+	Background Color
+
+	// Draws the cursor.
+	ShowCursor bool
+}
+
+// Returns the options with defaults applied.
+func (op *RenderOptions) withDefaults() RenderOptions {
+	out := RenderOptions{}
+	if op != nil {
+		out = *op
+	}
+
+	// The font family is written into CSS, only characters of font names and lists are kept.
+	out.FontFamily = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(" ,-_.'\"", r) {
+			return r
+		}
+		return -1
+	}, out.FontFamily)
+
+	if strings.TrimSpace(out.FontFamily) == "" {
+		out.FontFamily = "ui-monospace, Menlo, Consolas, 'DejaVu Sans Mono', monospace"
+	}
+
+	if out.FontSize == 0 {
+		out.FontSize = 14
+	}
+
+	if out.Foreground.IsDefault() {
+		out.Foreground = IndexedColor(7)
+	}
+
+	if out.Background.IsDefault() {
+		out.Background = IndexedColor(0)
+	}
+
+	return out
+}
+
+// A run of cells on a line sharing the same style.
+type span struct {
+	x     int
+	cols  int
+	text  string
+	style Cell
+}
+
+// Splits a line into runs of cells with the same style.
+// The cursor cell is split into its own run.
+func (s *Screen) spans(y int, op RenderOptions) []span {
+	out := make([]span, 0)
+	var cur *span
+	var text strings.Builder
+	flush := func() {
+		if cur != nil {
+			cur.text = text.String()
+			out = append(out, *cur)
+			cur = nil
+			text.Reset()
+		}
+	}
+
+	for x, c := range s.Cells[y] {
+		if c.Padding {
+			if cur != nil {
+				cur.cols++
+			}
+			continue
+		}
+
+		cursor := op.ShowCursor && s.Cursor == Position{X: x, Y: y}
+		if cursor {
+			c.Attrs ^= AttrReverse
+		}
+
+		if cur == nil || !cur.style.SameStyle(c) || cursor || (op.ShowCursor && s.Cursor == Position{X: x - 1, Y: y}) {
+			flush()
+			cur = &span{x: x, style: c}
+		}
+
+		r := c.Rune
+		if r == 0 {
+			r = ' '
+		}
+		text.WriteRune(r)
+		cur.cols++
+	}
+	flush()
+
+	return out
+}
+
+// Resolves the foreground and background colours of a cell, applying reverse and hidden.
+func (op RenderOptions) colors(c Cell) (string, string) {
+	fg, bg := c.Fg, c.Bg
+	if fg.IsDefault() {
+		fg = op.Foreground
+	}
+	if bg.IsDefault() {
+		bg = op.Background
+	}
+
+	// Bold named colours are shown in their bright variant, like most terminals.
+	if c.Attrs.Has(AttrBold) && fg.Kind == ColorIndexed && fg.Index < 8 && !c.Fg.IsDefault() {
+		fg = IndexedColor(fg.Index + 8)
+	}
+
+	if c.Attrs.Has(AttrReverse) {
+		fg, bg = bg, fg
+	}
+
+	if c.Attrs.Has(AttrHidden) {
+		fg = bg
+	}
+
+	return fg.Hex(DefaultColor), bg.Hex(DefaultColor)
+}
+
+// Returns the CSS text decoration of the cell.
+func textDecoration(c Cell) string {
+	lines := make([]string, 0)
+	if c.Attrs.Underlined() {
+		lines = append(lines, "underline")
+	}
+	if c.Attrs.Has(AttrStrikethrough) {
+		lines = append(lines, "line-through")
+	}
+	if c.Attrs.Has(AttrOverline) {
+		lines = append(lines, "overline")
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	decoration := strings.Join(lines, " ")
+	switch {
+	case c.Attrs.Has(AttrDoubleUnderline):
+		decoration += " double"
+	case c.Attrs.Has(AttrCurlyUnderline):
+		decoration += " wavy"
+	case c.Attrs.Has(AttrDottedUnderline):
+		decoration += " dotted"
+	case c.Attrs.Has(AttrDashedUnderline):
+		decoration += " dashed"
+	}
+
+	return decoration
+}
+
+// Returns the CSS declarations for a cell, excluding colours.
+func fontStyle(c Cell) []string {
+	out := make([]string, 0)
+	if c.Attrs.Has(AttrBold) {
+		out = append(out, "font-weight:bold")
+	}
+	if c.Attrs.Has(AttrItalic) {
+		out = append(out, "font-style:italic")
+	}
+	if c.Attrs.Has(AttrDim) {
+		out = append(out, "opacity:0.6")
+	}
+	if d := textDecoration(c); d != "" {
+		out = append(out, "text-decoration:"+d)
+	}
+	return out
+}
+
+// Renders the screen as a standalone HTML document.
+func (s *Screen) WriteHTML(w io.Writer, op *RenderOptions) error {
+	o := op.withDefaults()
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", html.EscapeString(o.Title))
+	fmt.Fprintf(&b, "<style>\npre.screen { margin: 0; padding: 0.5em; display: inline-block; font-family: %s; font-size: %dpx; line-height: 1.2; color: %s; background: %s; }\npre.screen a { color: inherit; }\n</style>\n",
+		o.FontFamily, o.FontSize, o.Foreground.Hex(DefaultColor), o.Background.Hex(DefaultColor))
+	b.WriteString("</head>\n<body>\n<pre class=\"screen\">")
+
+	defaultFg, defaultBg := o.colors(Cell{})
+	for y := 0; y < s.Height; y++ {
+		for _, sp := range s.spans(y, o) {
+			fg, bg := o.colors(sp.style)
+			decl := fontStyle(sp.style)
+			if fg != defaultFg {
+				decl = append(decl, "color:"+fg)
+			}
+			if bg != defaultBg {
+				decl = append(decl, "background:"+bg)
+			}
+
+			text := html.EscapeString(sp.text)
+			if len(decl) > 0 {
+				text = fmt.Sprintf("<span style=\"%s\">%s</span>", strings.Join(decl, ";"), text)
+			}
+			if safeLink(sp.style.Hyperlink) {
+				text = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(sp.style.Hyperlink), text)
+			}
+			b.WriteString(text)
+		}
+
+		if y < s.Height-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("</pre>\n</body>\n</html>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Renders the screen as a standalone SVG image.
+func (s *Screen) WriteSVG(w io.Writer, op *RenderOptions) error {
+	o := op.withDefaults()
+	cellW := float64(o.FontSize) * 0.6
+	cellH := float64(o.FontSize) * 1.2
+	width := cellW * float64(s.Width)
+	height := cellH * float64(s.Height)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.1f\" height=\"%.1f\" viewBox=\"0 0 %.1f %.1f\">\n", width, height, width, height)
+	if o.Title != "" {
+		fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(o.Title))
+	}
+
+	_, defaultBg := o.colors(Cell{})
+	fmt.Fprintf(&b, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", defaultBg)
+	fmt.Fprintf(&b, "<g font-family=\"%s\" font-size=\"%d\" xml:space=\"preserve\">\n", html.EscapeString(o.FontFamily), o.FontSize)
+
+	for y := 0; y < s.Height; y++ {
+		top := cellH * float64(y)
+		baseline := top + float64(o.FontSize)
+		for _, sp := range s.spans(y, o) {
+			x := cellW * float64(sp.x)
+			spanW := cellW * float64(sp.cols)
+			fg, bg := o.colors(sp.style)
+			if bg != defaultBg {
+				fmt.Fprintf(&b, "<rect x=\"%.1f\" y=\"%.1f\" width=\"%.1f\" height=\"%.1f\" fill=\"%s\"/>\n", x, top, spanW, cellH, bg)
+			}
+
+			if strings.TrimSpace(sp.text) == "" && !sp.style.Attrs.Underlined() && !sp.style.Attrs.Has(AttrStrikethrough) {
+				continue
+			}
+
+			attrs := []string{
+				fmt.Sprintf("x=\"%.1f\"", x),
+				fmt.Sprintf("y=\"%.1f\"", baseline),
+				fmt.Sprintf("fill=\"%s\"", fg),
+				fmt.Sprintf("textLength=\"%.1f\"", spanW),
+				"lengthAdjust=\"spacingAndGlyphs\"",
+			}
+			if decl := fontStyle(sp.style); len(decl) > 0 {
+				attrs = append(attrs, fmt.Sprintf("style=\"%s\"", strings.Join(decl, ";")))
+			}
+
+			text := fmt.Sprintf("<text %s>%s</text>", strings.Join(attrs, " "), html.EscapeString(sp.text))
+			if safeLink(sp.style.Hyperlink) {
+				text = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(sp.style.Hyperlink), text)
+			}
+			b.WriteString(text)
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("</g>\n</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,252 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package screen
+
+import (
+	"strings"
+	"testing"
+)
+
+func renderHTML(t *testing.T, s *Screen, op *RenderOptions) string {
+	t.Helper()
+	var b strings.Builder
+	if err := s.WriteHTML(&b, op); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func renderSVG(t *testing.T, s *Screen, op *RenderOptions) string {
+	t.Helper()
+	var b strings.Builder
+	if err := s.WriteSVG(&b, op); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestWriteHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		op      *RenderOptions
+		want    []string
+		exclude []string
+	}{
+		{
+			"text escaped", "<b>&", nil,
+			[]string{"&lt;b&gt;&amp;"},
+			[]string{"<b>"},
+		},
+		{
+			"title escaped", "a", &RenderOptions{Title: "</title><script>"},
+			[]string{"<title>&lt;/title&gt;&lt;script&gt;</title>"},
+			[]string{"<script>"},
+		},
+		{
+			"font family", "a", &RenderOptions{FontFamily: "'Fira Code', monospace"},
+			[]string{"font-family: 'Fira Code', monospace;"},
+			nil,
+		},
+		{
+			"font family sanitized", "a", &RenderOptions{FontFamily: "x; } </style><script>alert(1)</script>"},
+			[]string{"font-family: x  stylescriptalert1script;"},
+			[]string{"</style><script>", "alert(1)"},
+		},
+		{
+			"font family default", "a", &RenderOptions{FontFamily: "{};"},
+			[]string{"font-family: ui-monospace, Menlo, Consolas, 'DejaVu Sans Mono', monospace;"},
+			nil,
+		},
+		{
+			"default colours", "a", nil,
+			[]string{"color: #e5e5e5; background: #000000;", "<pre class=\"screen\">a   </pre>"},
+			[]string{"<span"},
+		},
+		{
+			"indexed colours", "\x1b[31;42mab\x1b[0mc", nil,
+			[]string{"<span style=\"color:#cd0000;background:#00cd00\">ab</span>c"},
+			nil,
+		},
+		{
+			"rgb colour", "\x1b[38;2;1;2;3ma", nil,
+			[]string{"<span style=\"color:#010203\">a</span>"},
+			nil,
+		},
+		{
+			"bold bright", "\x1b[1;34ma", nil,
+			[]string{"<span style=\"font-weight:bold;color:#5c5cff\">a</span>"},
+			nil,
+		},
+		{
+			"reverse", "\x1b[7ma", nil,
+			[]string{"<span style=\"color:#000000;background:#e5e5e5\">a</span>"},
+			nil,
+		},
+		{
+			"hyperlink", "\x1b]8;;https://example.com/?a=1&b=\"2\"\x1b\\link\x1b]8;;\x1b\\", nil,
+			[]string{"<a href=\"https://example.com/?a=1&amp;b=&#34;2&#34;\">link</a>"},
+			nil,
+		},
+		{
+			"unsafe hyperlink", "\x1b]8;;javascript:alert(1)\x1b\\link\x1b]8;;\x1b\\", nil,
+			[]string{">link</pre>"},
+			[]string{"<a ", "javascript"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderHTML(t, Parse(tt.content, 4, 1), tt.op)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, exclude := range tt.exclude {
+				if strings.Contains(got, exclude) {
+					t.Errorf("output contains %q:\n%s", exclude, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteHTMLCursor(t *testing.T) {
+	s := Parse("ab", 2, 1)
+	s.Cursor = Position{X: 1, Y: 0}
+
+	got := renderHTML(t, s, &RenderOptions{ShowCursor: true})
+	want := "<pre class=\"screen\">a<span style=\"color:#000000;background:#e5e5e5\">b</span></pre>"
+	if !strings.Contains(got, want) {
+		t.Errorf("output does not contain %q:\n%s", want, got)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		op      *RenderOptions
+		want    []string
+		exclude []string
+	}{
+		{
+			"text escaped", "<&>", nil,
+			[]string{">&lt;&amp;&gt; </text>"},
+			nil,
+		},
+		{
+			"title escaped", "a", &RenderOptions{Title: "<x>"},
+			[]string{"<title>&lt;x&gt;</title>"},
+			nil,
+		},
+		{
+			"font family", "a", &RenderOptions{FontFamily: "'Fira Code', monospace", FontSize: 10},
+			[]string{"<g font-family=\"&#39;Fira Code&#39;, monospace\" font-size=\"10\""},
+			nil,
+		},
+		{
+			"font family sanitized", "a", &RenderOptions{FontFamily: "x\"><script>"},
+			[]string{"<g font-family=\"x&#34;script\""},
+			[]string{"<script>"},
+		},
+		{
+			"colours", "\x1b[31;42ma", &RenderOptions{FontSize: 10},
+			[]string{
+				"<rect width=\"100%\" height=\"100%\" fill=\"#000000\"/>",
+				"<rect x=\"0.0\" y=\"0.0\" width=\"6.0\" height=\"12.0\" fill=\"#00cd00\"/>",
+				"fill=\"#cd0000\"",
+			},
+			nil,
+		},
+		{
+			"hyperlink", "\x1b]8;;https://example.com/?a&b\x1b\\link\x1b]8;;\x1b\\", nil,
+			[]string{"<a href=\"https://example.com/?a&amp;b\"><text "},
+			nil,
+		},
+		{
+			"unsafe hyperlink", "\x1b]8;;javascript:alert(1)\x1b\\link\x1b]8;;\x1b\\", nil,
+			[]string{">link</text>"},
+			[]string{"<a ", "javascript"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderSVG(t, Parse(tt.content, 4, 1), tt.op)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, exclude := range tt.exclude {
+				if strings.Contains(got, exclude) {
+					t.Errorf("output contains %q:\n%s", exclude, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCompose(t *testing.T) {
+	left := Parse("ab\ncd", 2, 2)
+	left.Cursor = Position{X: 1, Y: 1}
+	right := Parse("ef\ngh", 2, 2)
+	bottom := Parse("ijklm", 5, 1)
+
+	s := Compose(5, 4, []Region{
+		{X: 0, Y: 0, Screen: left, Active: true},
+		{X: 3, Y: 0, Screen: right},
+		{X: 0, Y: 3, Screen: bottom},
+	})
+
+	want := []string{
+		"ab│ef",
+		"cd│gh",
+		"──┴──",
+		"ijklm",
+	}
+	got := s.Lines()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if s.Cursor != (Position{X: 1, Y: 1}) {
+		t.Errorf("cursor = %+v, want {1 1}", s.Cursor)
+	}
+
+	// Borders touching the active region are highlighted.
+	for _, p := range []Position{{2, 0}, {2, 1}, {0, 2}, {2, 2}} {
+		if c := s.Cell(p.X, p.Y); c.Fg != activeBorderColor {
+			t.Errorf("border at %+v = %+v, want the active colour", p, c.Fg)
+		}
+	}
+	for _, p := range []Position{{3, 2}, {4, 2}} {
+		if c := s.Cell(p.X, p.Y); !c.Fg.IsDefault() {
+			t.Errorf("border at %+v = %+v, want the default colour", p, c.Fg)
+		}
+	}
+}
+
+func TestComposeActiveCursor(t *testing.T) {
+	left := Parse("a\nb", 1, 2)
+	right := Parse("c\nd", 1, 2)
+	right.Cursor = Position{X: 0, Y: 1}
+
+	s := Compose(3, 2, []Region{
+		{X: 0, Y: 0, Screen: left},
+		{X: 2, Y: 0, Screen: right, Active: true},
+	})
+
+	if got := s.Line(1); got != "b│d" {
+		t.Errorf("line = %q, want %q", got, "b│d")
+	}
+	if s.Cursor != (Position{X: 2, Y: 1}) {
+		t.Errorf("cursor = %+v, want {2 1}", s.Cursor)
+	}
+}
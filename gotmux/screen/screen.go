@@ -0,0 +1,233 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+// Package screen models the content of a tmux pane as a grid of cells.
+// It parses the output of capture-pane with escape sequences (-e).
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+package screen
+
+import (
+	"strings"
+)
+
+// Cell text attributes.
+type Attr uint16
+
+// Enumeration of cell attributes.
+const (
+	AttrBold Attr = 1 << iota
+	AttrDim
+	AttrItalic
+	AttrUnderline
+	AttrDoubleUnderline
+	AttrCurlyUnderline
+	AttrDottedUnderline
+	AttrDashedUnderline
+	AttrBlink
+	AttrReverse
+	AttrHidden
+	AttrStrikethrough
+	AttrOverline
+)
+
+// All the underline attributes.
+const attrUnderlines = AttrUnderline | AttrDoubleUnderline | AttrCurlyUnderline | AttrDottedUnderline | AttrDashedUnderline
+
+// Returns true if all the provided attributes are set.
+func (a Attr) Has(attr Attr) bool {
+	return a&attr == attr
+}
+
+// Returns true if any underline style is set.
+func (a Attr) Underlined() bool {
+	return a&attrUnderlines != 0
+}
+
+// A single cell of the screen.
+// Wide characters occupy two cells, the second one is a padding cell.
+type Cell struct {
+	Rune      rune
+	Fg        Color
+	Bg        Color
+	Attrs     Attr
+	Hyperlink string
+	Padding   bool
+}
+
+// Returns true if the cell has no content and no styling.
+func (c Cell) IsBlank() bool {
+	return (c.Rune == ' ' || c.Rune == 0) &&
+		c.Fg.IsDefault() &&
+		c.Bg.IsDefault() &&
+		c.Attrs == 0 &&
+		c.Hyperlink == ""
+}
+
+// Returns true if both cells have the same style.
+func (c Cell) SameStyle(o Cell) bool {
+	return c.Fg == o.Fg &&
+		c.Bg == o.Bg &&
+		c.Attrs == o.Attrs &&
+		c.Hyperlink == o.Hyperlink
+}
+
+// A position on the screen.
+type Position struct {
+	X int
+	Y int
+}
+
+// Grid of cells representing the content of a pane.
+type Screen struct {
+	Width  int
+	Height int
+	Cells  [][]Cell
+	Cursor Position
+}
+
+// Creates a blank screen with the given size.
+func New(width, height int) *Screen {
+	s := &Screen{
+		Width:  width,
+		Height: height,
+		Cells:  make([][]Cell, height),
+	}
+	for y := range s.Cells {
+		s.Cells[y] = blankLine(width)
+	}
+	return s
+}
+
+// Returns a line of blank cells.
+func blankLine(width int) []Cell {
+	line := make([]Cell, width)
+	for x := range line {
+		line[x] = Cell{Rune: ' '}
+	}
+	return line
+}
+
+// Returns the cell at a position.
+// Returns a blank cell if the position is out of bounds.
+func (s *Screen) Cell(x, y int) Cell {
+	if !s.inBounds(x, y) {
+		return Cell{Rune: ' '}
+	}
+	return s.Cells[y][x]
+}
+
+// Sets the cell at a position. Ignored if out of bounds.
+func (s *Screen) SetCell(x, y int, c Cell) {
+	if !s.inBounds(x, y) {
+		return
+	}
+	s.Cells[y][x] = c
+}
+
+// Returns the text of a line, with trailing spaces removed.
+func (s *Screen) Line(y int) string {
+	if y < 0 || y >= s.Height {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, c := range s.Cells[y] {
+		if c.Padding {
+			continue
+		}
+		if c.Rune == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(c.Rune)
+	}
+
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Returns the text of all the lines.
+func (s *Screen) Lines() []string {
+	out := make([]string, 0, s.Height)
+	for y := 0; y < s.Height; y++ {
+		out = append(out, s.Line(y))
+	}
+	return out
+}
+
+// Returns the plain text of the screen.
+func (s *Screen) String() string {
+	return strings.Join(s.Lines(), "\n")
+}
+
+// Finds all the occurrences of a string on the screen.
+// Matches do not span lines. Returns the positions of the first cells.
+func (s *Screen) Find(text string) []Position {
+	out := make([]Position, 0)
+	if text == "" {
+		return out
+	}
+
+	needle := []rune(text)
+	for y := 0; y < s.Height; y++ {
+		runes, cols := s.lineRunes(y)
+		for i := 0; i+len(needle) <= len(runes); i++ {
+			if equalRunes(runes[i:i+len(needle)], needle) {
+				out = append(out, Position{X: cols[i], Y: y})
+			}
+		}
+	}
+
+	return out
+}
+
+// Returns the positions of the cells that differ between two screens.
+// The screens are compared over the largest common size,
+// cells outside of it are reported as different.
+func (s *Screen) Diff(o *Screen) []Position {
+	out := make([]Position, 0)
+	width := max(s.Width, o.Width)
+	height := max(s.Height, o.Height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !s.inBounds(x, y) || !o.inBounds(x, y) || s.Cells[y][x] != o.Cells[y][x] {
+				out = append(out, Position{X: x, Y: y})
+			}
+		}
+	}
+	return out
+}
+
+// Returns the runes of a line without padding cells, along with their columns.
+func (s *Screen) lineRunes(y int) ([]rune, []int) {
+	runes := make([]rune, 0, s.Width)
+	cols := make([]int, 0, s.Width)
+	for x, c := range s.Cells[y] {
+		if c.Padding {
+			continue
+		}
+		r := c.Rune
+		if r == 0 {
+			r = ' '
+		}
+		runes = append(runes, r)
+		cols = append(cols, x)
+	}
+	return runes, cols
+}
+
+// Returns true if the position is inside the screen.
+func (s *Screen) inBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && y < s.Height && x < s.Width
+}
+
+// Compares two rune slices.
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -6,12 +6,14 @@ package gotmux
 
 import (
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Server struct {
 	Pid       int32
 	Socket    *Socket
-	StartTime string
+	StartTime time.Time
 	Uid       string
 	User      string
 	Version   string
@@ -30,11 +32,14 @@ func (q *query) serverVars() *query {
 	)
 }
 
-func (q queryResult) toServer(t *Tmux) *Server {
+func (q queryResult) toServer(t *Tmux) (*Server, error) {
 	pid, _ := strconv.Atoi(q.get(varPid))
 	socketPath := q.get(varSocketPath)
 	socket, _ := newSocket(socketPath)
-	startTime := q.get(varStartTime)
+	startTime, err := parseTime(q.get(varStartTime))
+	if err != nil {
+		return nil, err
+	}
 	uid := q.get(varUid)
 	user := q.get(varUser)
 	version := q.get(varVersion)
@@ -50,5 +55,93 @@ func (q queryResult) toServer(t *Tmux) *Server {
 		tmux: t,
 	}
 
-	return s
+	return s, nil
+}
+
+// Returns the time since the server started.
+func (s *Server) Uptime() time.Duration {
+	return time.Since(s.StartTime)
+}
+
+// Returns true if the server version is at least the provided version, such as "3.2" or "3.3a".
+// Development versions such as "next-3.4" are considered newer than the release they follow,
+// and versions that cannot be parsed newer than any release.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#version
+func (s *Server) VersionAtLeast(min string) bool {
+	return compareVersions(s.Version, min) >= 0
+}
+
+// A parsed tmux version such as "3.3a".
+type version struct {
+	major, minor int
+	patch        byte
+
+	// Development version following the release, such as "next-3.4".
+	next bool
+
+	// Version that cannot be parsed, such as "master".
+	dev bool
+}
+
+// Parses a tmux version. Versions that cannot be parsed are considered development versions.
+func parseVersion(v string) version {
+	v, next := strings.CutPrefix(v, "next-")
+	major, rest, ok := strings.Cut(v, ".")
+	if !ok {
+		return version{dev: true}
+	}
+
+	maj, err := strconv.Atoi(major)
+	if err != nil {
+		return version{dev: true}
+	}
+
+	i := 0
+	for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+		i++
+	}
+
+	min, err := strconv.Atoi(rest[:i])
+	if err != nil {
+		return version{dev: true}
+	}
+
+	out := version{major: maj, minor: min, next: next}
+	if i < len(rest) {
+		out.patch = rest[i]
+	}
+
+	return out
+}
+
+// Compares two tmux versions. Returns -1, 0 or 1.
+func compareVersions(a, b string) int {
+	va, vb := parseVersion(a), parseVersion(b)
+	switch {
+	case va.dev && vb.dev:
+		return 0
+	case va.dev:
+		return 1
+	case vb.dev:
+		return -1
+	}
+
+	for _, d := range []int{va.major - vb.major, va.minor - vb.minor, int(va.patch) - int(vb.patch)} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case va.next && !vb.next:
+		return 1
+	case vb.next && !va.next:
+		return -1
+	}
+
+	return 0
 }
@@ -0,0 +1,34 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.3a", "3.3a", 0},
+		{"3.3a", "3.3", 1},
+		{"3.2", "3.3", -1},
+		{"2.9", "3.0", -1},
+		{"3.10", "3.9", 1},
+		{"next-3.4", "3.4", 1},
+		{"next-3.4", "3.3a", 1},
+		{"next-3.4", "next-3.4", 0},
+		{"3.4", "next-3.4", -1},
+		{"3.5", "next-3.4", 1},
+		{"master", "3.4", 1},
+		{"3.4", "master", -1},
+		{"master", "next-3.4", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -8,17 +8,19 @@ import (
 	"errors"
 	"io"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Tmux session object.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
 type Session struct {
-	Activity          string
+	Activity          time.Time
 	Alerts            string
 	Attached          int
 	AttachedList      []string
-	Created           string
+	Created           time.Time
 	Format            bool
 	Group             string
 	GroupAttached     int
@@ -28,7 +30,7 @@ type Session struct {
 	GroupSize         int
 	Grouped           bool
 	Id                string
-	LastAttached      string
+	LastAttached      time.Time
 	ManyAttached      bool
 	Marked            bool
 	Name              string
@@ -77,7 +79,7 @@ type AttachSessionOptions struct {
 func (s *Session) AttachSession(op *AttachSessionOptions) error {
 	q := s.tmux.query().
 		cmd("attach-session").
-		fargs("-t", s.Name)
+		fargs("-t", s.Id)
 
 	if op != nil {
 		if op.DetachClients {
@@ -115,7 +117,7 @@ func (s *Session) Attach() error {
 func (s *Session) Detach() error {
 	_, err := s.tmux.query().
 		cmd("detach-client").
-		fargs("-s", s.Name).run()
+		fargs("-s", s.Id).run()
 	if err != nil {
 		return errors.New("failed to detach session")
 	}
@@ -123,13 +125,50 @@ func (s *Session) Detach() error {
 	return nil
 }
 
+// Re-reads the fields of the session from tmux, by session id.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#display-message
+func (s *Session) Refresh() error {
+	o, err := s.tmux.query().
+		cmd("display-message").
+		fargs("-t", s.Id).
+		sessionVars().
+		run()
+	if err != nil {
+		return errors.New("failed to refresh session")
+	}
+
+	fresh, err := o.one().toSession(s.tmux)
+	if err != nil {
+		return err
+	}
+
+	// Targets that do not exist are not an error for display-message, but expand to empty values.
+	if fresh.Id != s.Id {
+		return errors.New("session not found")
+	}
+
+	*s = *fresh
+	return nil
+}
+
+// Returns the time since the last activity in the session.
+func (s *Session) IdleFor() time.Duration {
+	return time.Since(s.Activity)
+}
+
+// Returns the time since the session was created.
+func (s *Session) Age() time.Duration {
+	return time.Since(s.Created)
+}
+
 // Kills the session.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#kill-session
 func (s *Session) Kill() error {
 	_, err := s.tmux.query().
 		cmd("kill-session").
-		fargs("-t", s.Name).
+		fargs("-t", s.Id).
 		run()
 	if err != nil {
 		return errors.New("failed to kill session")
@@ -144,13 +183,14 @@ func (s *Session) Kill() error {
 func (s *Session) Rename(name string) error {
 	_, err := s.tmux.query().
 		cmd("rename-session").
-		fargs("-t", s.Name).
+		fargs("-t", s.Id).
 		pargs(name).
 		run()
 	if err != nil {
 		return errors.New("failed to rename session")
 	}
 
+	s.Name = name
 	return nil
 }
 
@@ -160,7 +200,7 @@ func (s *Session) Rename(name string) error {
 func (s *Session) ListWindows() ([]*Window, error) {
 	o, err := s.tmux.query().
 		cmd("list-windows").
-		fargs("-t", s.Name).
+		fargs("-t", s.Id).
 		windowVars().
 		run()
 	if err != nil {
@@ -170,7 +210,10 @@ func (s *Session) ListWindows() ([]*Window, error) {
 	qr := o.collect()
 	out := make([]*Window, 0)
 	for _, item := range qr {
-		w := item.toWindow(s.tmux)
+		w, err := item.toWindow(s.tmux)
+		if err != nil {
+			return nil, err
+		}
 		out = append(out, w)
 	}
 
@@ -183,7 +226,7 @@ func (s *Session) ListWindows() ([]*Window, error) {
 func (s *Session) ListPanes() ([]*Pane, error) {
 	o, err := s.tmux.query().
 		cmd("list-panes").
-		fargs("-s", "-t", s.Name).
+		fargs("-s", "-t", s.Id).
 		paneVars().
 		run()
 	if err != nil {
@@ -192,7 +235,10 @@ func (s *Session) ListPanes() ([]*Pane, error) {
 
 	out := make([]*Pane, 0)
 	for _, item := range o.collect() {
-		pane := item.toPane(s.tmux)
+		pane, err := item.toPane(s.tmux)
+		if err != nil {
+			return nil, err
+		}
 		out = append(out, pane)
 	}
 
@@ -238,18 +284,52 @@ type NewWindowOptions struct {
 	StartDirectory string
 	WindowName     string
 	DoNotAttach    bool
+
+	// Index of the new window. The next free index is used if nil.
+	// With InsertAfter or InsertBefore, the index of the window to insert next to.
+	Index *int
+
+	// Inserts the window after or before the window at Index, or the current window,
+	// moving the following windows up.
+	InsertAfter  bool
+	InsertBefore bool
+
+	// Destroys the window at Index if it already exists.
+	KillExisting bool
+
+	// Selects the existing window with the same WindowName instead of creating a new one.
+	SelectExisting bool
+
+	// Environment variables set in the window.
+	Environment map[string]string
+
+	// Command run by the shell in the window.
+	ShellCommand string
+
+	// Command and arguments run directly, without a shell. Not used with ShellCommand.
+	Command []string
 }
 
 // Creates a new window in this session.
+// With SelectExisting, the existing window is returned when there is one.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#new-window
 func (s *Session) NewWindow(op *NewWindowOptions) (*Window, error) {
+	target := s.Id + ":"
+	if op != nil && op.Index != nil {
+		target += strconv.Itoa(*op.Index)
+	}
+
 	q := s.tmux.query().
 		cmd("new-window").
-		fargs("-P", "-t", s.Name).
+		fargs("-P", "-t", target).
 		windowVars()
 
 	if op != nil {
+		if op.ShellCommand != "" && len(op.Command) > 0 {
+			return nil, errors.New("shell command and command are exclusive")
+		}
+
 		if op.StartDirectory != "" {
 			q.fargs("-c", op.StartDirectory)
 		}
@@ -261,6 +341,34 @@ func (s *Session) NewWindow(op *NewWindowOptions) (*Window, error) {
 		if op.DoNotAttach {
 			q.fargs("-d")
 		}
+
+		if op.InsertAfter {
+			q.fargs("-a")
+		}
+
+		if op.InsertBefore {
+			q.fargs("-b")
+		}
+
+		if op.KillExisting {
+			q.fargs("-k")
+		}
+
+		if op.SelectExisting {
+			q.fargs("-S")
+		}
+
+		for _, e := range environmentArgs(op.Environment) {
+			q.fargs("-e", e)
+		}
+
+		if op.ShellCommand != "" {
+			q.pargs(op.ShellCommand)
+		}
+
+		if len(op.Command) > 0 {
+			q.pargs(op.Command...)
+		}
 	}
 
 	o, err := q.run()
@@ -268,8 +376,23 @@ func (s *Session) NewWindow(op *NewWindowOptions) (*Window, error) {
 		return nil, errors.New("failed to create window")
 	}
 
-	w := o.one().toWindow(s.tmux)
-	return w, nil
+	// Windows may also be destroyed or selected, so the window count is fetched again.
+	err = s.Refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing is printed when an existing window is selected.
+	if op != nil && op.SelectExisting && strings.TrimSpace(o.raw()) == "" {
+		w, err := s.GetWindowByName(op.WindowName)
+		if err != nil || w == nil {
+			return nil, errors.New("failed to get existing window")
+		}
+
+		return w, nil
+	}
+
+	return o.one().toWindow(s.tmux)
 }
 
 // Creates a new window in this session.
@@ -286,7 +409,7 @@ func (s *Session) New() (*Window, error) {
 func (s *Session) NextWindow() error {
 	q := s.tmux.query().
 		cmd("next-window").
-		fargs("-t", s.Name)
+		fargs("-t", s.Id)
 
 	_, err := q.run()
 	if err != nil {
@@ -302,7 +425,7 @@ func (s *Session) NextWindow() error {
 func (s *Session) PreviousWindow() error {
 	q := s.tmux.query().
 		cmd("previous-window").
-		fargs("-t", s.Name)
+		fargs("-t", s.Id)
 
 	_, err := q.run()
 	if err != nil {
@@ -317,28 +440,35 @@ func (s *Session) PreviousWindow() error {
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
 func (s *Session) SetOption(key, option string) error {
-	return s.tmux.SetOption(s.Name, key, option, "")
+	return s.tmux.SetOption(s.Target(), key, option, OptionScopeSession)
 }
 
 // Retrieves an option from this session.
 //
 // https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
 func (s *Session) Option(key string) (*Option, error) {
-	return s.tmux.Option(s.Name, key, "")
+	return s.tmux.Option(s.Target(), key, OptionScopeSession)
 }
 
 // Retrieves all options in this session.
 //
 // https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
 func (s *Session) Options() ([]*Option, error) {
-	return s.tmux.Options(s.Name, "")
+	return s.tmux.Options(s.Target(), OptionScopeSession)
+}
+
+// Lists the schemas of the built-in options that can be set on this session.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (s *Session) OptionSchemas() ([]*OptionSchema, error) {
+	return s.tmux.OptionSchemas(OptionScopeSession)
 }
 
 // Deletes an option from this session.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
 func (s *Session) DeleteOption(key string) error {
-	return s.tmux.DeleteOption(s.Name, key, "")
+	return s.tmux.DeleteOption(s.Target(), key, OptionScopeSession)
 }
 
 // Sets the session variables in the query.
@@ -369,12 +499,18 @@ func (q *query) sessionVars() *query {
 }
 
 // Converts a QueryResult to a Session.
-func (q queryResult) toSession(t *Tmux) *Session {
-	activity := q.get(varSessionActivity)
+func (q queryResult) toSession(t *Tmux) (*Session, error) {
+	activity, err := parseTime(q.get(varSessionActivity))
+	if err != nil {
+		return nil, err
+	}
 	alerts := q.get(varSessionAlerts)
 	attached, _ := strconv.Atoi(q.get(varSessionAttached))
 	attachedList := parseList(q.get(varSessionAttachedList))
-	created := q.get(varSessionCreated)
+	created, err := parseTime(q.get(varSessionCreated))
+	if err != nil {
+		return nil, err
+	}
 	format := isOne(q.get(varSessionFormat))
 	group := q.get(varSessionGroup)
 	groupAttached, _ := strconv.Atoi(q.get(varSessionGroupAttached))
@@ -384,7 +520,10 @@ func (q queryResult) toSession(t *Tmux) *Session {
 	groupSize, _ := strconv.Atoi(q.get(varSessionGroupSize))
 	grouped := isOne(q.get(varSessionGrouped))
 	id := q.get(varSessionId)
-	lastAttached := q.get(varSessionLastAttached)
+	lastAttached, err := parseTime(q.get(varSessionLastAttached))
+	if err != nil {
+		return nil, err
+	}
 	manyAttached := isOne(q.get(varSessionManyAttached))
 	marked := isOne(q.get(varSessionMarked))
 	name := q.get(varSessionName)
@@ -418,5 +557,5 @@ func (q queryResult) toSession(t *Tmux) *Session {
 		tmux: t,
 	}
 
-	return s
+	return s, nil
 }
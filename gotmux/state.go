@@ -0,0 +1,181 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"strings"
+)
+
+// Cursor of a pane, and the cursor of copy mode when the pane is in copy mode.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
+type PaneCursor struct {
+	X       int  `tmux:"cursor_x"`
+	Y       int  `tmux:"cursor_y"`
+	Visible bool `tmux:"cursor_flag"`
+
+	CopyX int `tmux:"copy_cursor_x"`
+	CopyY int `tmux:"copy_cursor_y"`
+
+	// Character under the cursor, and word and line under the copy mode cursor.
+	// Printed on their own lines since they may contain anything, including the query separator.
+	Character string
+	CopyWord  string
+	CopyLine  string
+}
+
+// Scrollback history of a pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
+type PaneHistory struct {
+	Size  int `tmux:"history_size"`
+	Limit int `tmux:"history_limit"`
+	Bytes int `tmux:"history_bytes"`
+
+	// Lines scrolled back in copy mode.
+	ScrollPosition int `tmux:"scroll_position"`
+}
+
+// Terminal modes of a pane, as set by the application running in it.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
+type PaneTerminal struct {
+	// The saved cursor position is 4294967295 when the alternate screen is off.
+	AlternateOn     bool `tmux:"alternate_on"`
+	AlternateSavedX int  `tmux:"alternate_saved_x"`
+	AlternateSavedY int  `tmux:"alternate_saved_y"`
+
+	Insert       bool `tmux:"insert_flag"`
+	KeypadCursor bool `tmux:"keypad_cursor_flag"`
+	Keypad       bool `tmux:"keypad_flag"`
+	Origin       bool `tmux:"origin_flag"`
+	Wrap         bool `tmux:"wrap_flag"`
+
+	ScrollRegionUpper int `tmux:"scroll_region_upper"`
+	ScrollRegionLower int `tmux:"scroll_region_lower"`
+
+	MouseAll      bool `tmux:"mouse_all_flag"`
+	MouseAny      bool `tmux:"mouse_any_flag"`
+	MouseButton   bool `tmux:"mouse_button_flag"`
+	MouseStandard bool `tmux:"mouse_standard_flag"`
+	MouseSgr      bool `tmux:"mouse_sgr_flag"`
+	MouseUtf8     bool `tmux:"mouse_utf8_flag"`
+}
+
+// Selection and search of a pane in copy mode.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
+type PaneSelection struct {
+	Active    bool `tmux:"selection_active"`
+	Present   bool `tmux:"selection_present"`
+	StartX    int  `tmux:"selection_start_x"`
+	StartY    int  `tmux:"selection_start_y"`
+	EndX      int  `tmux:"selection_end_x"`
+	EndY      int  `tmux:"selection_end_y"`
+	Rectangle bool `tmux:"rectangle_toggle"`
+
+	SearchPresent bool `tmux:"search_present"`
+
+	// Text of the search match, printed on its own line since it may contain the query separator.
+	SearchMatch string
+}
+
+// Queries a single object for the target.
+func queryOne[T any](t *Tmux, target Target) (*T, error) {
+	items, err := Query[T](t, target, QueryScopeTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("target not found")
+	}
+
+	return &items[0], nil
+}
+
+// Queries the tagged fields of T and free text variables of the pane in one command sequence, so that all the values match.
+// The free text values are printed on their own lines after the other values, since they may contain the query separator.
+func queryPaneText[T any](p *Pane, text ...string) (*T, []string, error) {
+	fields, vars, err := queryFields[T]()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := p.tmux.query().
+		cmd("display-message").
+		fargs("-t", p.Id).
+		vars(vars...)
+	for _, v := range text {
+		q.pargs(";", "display-message", "-p", "-t", p.Id, "--", "#{"+v+"}")
+	}
+
+	o, err := q.run()
+	if err != nil {
+		return nil, nil, errors.New("failed to run query")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(o.result, "\n"), "\n")
+	if len(lines) != len(text)+1 {
+		return nil, nil, errors.New("invalid query output, a value contains a newline")
+	}
+
+	results, err := (&queryOutput{result: lines[0], variables: vars}).collectStrict()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(results) != 1 {
+		return nil, nil, errors.New("target not found")
+	}
+
+	item, err := decodeResult[T](fields, results[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &item, lines[1:], nil
+}
+
+// Retrieves the cursor of the pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
+func (p *Pane) Cursor() (*PaneCursor, error) {
+	c, text, err := queryPaneText[PaneCursor](p, varCursorCharacter, varCopyCursorWord, varCopyCursorLine)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Character, c.CopyWord, c.CopyLine = text[0], text[1], text[2]
+	return c, nil
+}
+
+// Retrieves the scrollback history of the pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
+func (p *Pane) History() (*PaneHistory, error) {
+	return queryOne[PaneHistory](p.tmux, p.Target())
+}
+
+// Retrieves the terminal modes of the pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
+func (p *Pane) Terminal() (*PaneTerminal, error) {
+	return queryOne[PaneTerminal](p.tmux, p.Target())
+}
+
+// Retrieves the selection of the pane. Only meaningful when the pane is in copy mode.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#Variable
+func (p *Pane) Selection() (*PaneSelection, error) {
+	sel, text, err := queryPaneText[PaneSelection](p, varSearchMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	sel.SearchMatch = text[0]
+	return sel, nil
+}
@@ -0,0 +1,541 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind of a style colour.
+type ColourKind int
+
+// Enumeration of colour kinds.
+const (
+	// The colour is not set in the style.
+	ColourUnset ColourKind = iota
+
+	// The default colour, "default".
+	ColourDefault
+
+	// The colour of the terminal outside tmux, "terminal".
+	ColourTerminal
+
+	// A named colour such as "red", "brightblue" or an X11 colour name.
+	ColourNamed
+
+	// A colour of the 256 colour palette, "colourN".
+	ColourIndexed
+
+	// A 24 bit colour, "#rrggbb".
+	ColourRGB
+)
+
+// A colour used in styles.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#STYLES
+type Colour struct {
+	Kind ColourKind
+
+	// Name of named colours.
+	Name string
+
+	// Index of indexed colours.
+	Index int
+
+	// Components of RGB colours.
+	R, G, B uint8
+}
+
+// The default colour.
+var DefaultColour = Colour{Kind: ColourDefault}
+
+// The colour of the terminal outside tmux.
+var TerminalColour = Colour{Kind: ColourTerminal}
+
+// Creates a named colour such as "red" or "brightgreen".
+func NamedColour(name string) Colour {
+	return Colour{Kind: ColourNamed, Name: strings.ToLower(name)}
+}
+
+// Creates a colour of the 256 colour palette.
+func IndexedColour(index int) Colour {
+	return Colour{Kind: ColourIndexed, Index: index}
+}
+
+// Creates a 24 bit colour.
+func RGBColour(r, g, b uint8) Colour {
+	return Colour{Kind: ColourRGB, R: r, G: g, B: b}
+}
+
+// Parses a colour as accepted by tmux.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#STYLES
+func ParseColour(s string) (Colour, error) {
+	lower := strings.ToLower(s)
+	switch {
+	case lower == "":
+		return Colour{}, errors.New("empty colour")
+	case lower == "default":
+		return DefaultColour, nil
+	case lower == "terminal":
+		return TerminalColour, nil
+	case strings.HasPrefix(lower, "#"):
+		if len(lower) != 7 {
+			return Colour{}, fmt.Errorf("invalid colour %q", s)
+		}
+
+		v, err := strconv.ParseUint(lower[1:], 16, 32)
+		if err != nil {
+			return Colour{}, fmt.Errorf("invalid colour %q", s)
+		}
+
+		return RGBColour(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+	}
+
+	for _, prefix := range []string{"colour", "color"} {
+		n, ok := strings.CutPrefix(lower, prefix)
+		if !ok {
+			continue
+		}
+
+		i, err := strconv.Atoi(n)
+		if err != nil || i < 0 || i > 255 {
+			return Colour{}, fmt.Errorf("invalid colour %q", s)
+		}
+
+		return IndexedColour(i), nil
+	}
+
+	return NamedColour(lower), nil
+}
+
+// Returns true if the colour is set.
+func (c Colour) IsSet() bool {
+	return c.Kind != ColourUnset
+}
+
+// Formats the colour as accepted by tmux. Unset colours are empty.
+func (c Colour) String() string {
+	switch c.Kind {
+	case ColourDefault:
+		return "default"
+	case ColourTerminal:
+		return "terminal"
+	case ColourNamed:
+		return c.Name
+	case ColourIndexed:
+		return "colour" + strconv.Itoa(c.Index)
+	case ColourRGB:
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+
+	return ""
+}
+
+// Implements encoding.TextMarshaler, so colours are written as strings in JSON.
+func (c Colour) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// Implements encoding.TextUnmarshaler.
+func (c *Colour) UnmarshalText(text []byte) error {
+	parsed, err := ParseColour(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+	return nil
+}
+
+// Set of style attributes.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#STYLES
+type StyleAttr uint16
+
+// Enumeration of style attributes.
+const (
+	StyleBold StyleAttr = 1 << iota
+	StyleDim
+	StyleUnderscore
+	StyleBlink
+	StyleReverse
+	StyleHidden
+	StyleItalics
+	StyleOverline
+	StyleStrikethrough
+	StyleDoubleUnderscore
+	StyleCurlyUnderscore
+	StyleDottedUnderscore
+	StyleDashedUnderscore
+)
+
+// Names of the attributes, in the order they are serialized.
+var styleAttrNames = []struct {
+	attr StyleAttr
+	name string
+}{
+	{StyleBold, "bold"},
+	{StyleDim, "dim"},
+	{StyleUnderscore, "underscore"},
+	{StyleBlink, "blink"},
+	{StyleReverse, "reverse"},
+	{StyleHidden, "hidden"},
+	{StyleItalics, "italics"},
+	{StyleOverline, "overline"},
+	{StyleStrikethrough, "strikethrough"},
+	{StyleDoubleUnderscore, "double-underscore"},
+	{StyleCurlyUnderscore, "curly-underscore"},
+	{StyleDottedUnderscore, "dotted-underscore"},
+	{StyleDashedUnderscore, "dashed-underscore"},
+}
+
+// Finds an attribute by name. "bright" is an alias of "bold".
+func parseStyleAttr(name string) (StyleAttr, bool) {
+	if name == "bright" {
+		return StyleBold, true
+	}
+
+	for _, a := range styleAttrNames {
+		if a.name == name {
+			return a.attr, true
+		}
+	}
+
+	return 0, false
+}
+
+// Returns true if all the given attributes are set.
+func (a StyleAttr) Has(attr StyleAttr) bool {
+	return a&attr == attr
+}
+
+// Returns the names of the attributes in the set.
+func (a StyleAttr) Names() []string {
+	out := make([]string, 0)
+	for _, n := range styleAttrNames {
+		if a.Has(n.attr) {
+			out = append(out, n.name)
+		}
+	}
+
+	return out
+}
+
+// Enumeration of style alignments.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#STYLES
+const (
+	StyleAlignLeft           = "left"
+	StyleAlignCentre         = "centre"
+	StyleAlignRight          = "right"
+	StyleAlignAbsoluteCentre = "absolute-centre"
+)
+
+// A tmux style, such as "fg=colour1,bg=#000000,bold,align=centre".
+// Fields that are not set are omitted when serialized.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#STYLES
+type Style struct {
+	// Resets the foreground and background colours, "default".
+	Default bool
+
+	// Clears all attributes, "none".
+	None bool
+
+	// Foreground, background, underscore and fill colours.
+	Fg   Colour
+	Bg   Colour
+	Us   Colour
+	Fill Colour
+
+	// Attributes turned on, and attributes turned off with a "no" prefix.
+	Attrs   StyleAttr
+	NoAttrs StyleAttr
+
+	// Alignment of the status line, one of the StyleAlign constants. NoAlign resets it.
+	Align   string
+	NoAlign bool
+
+	// Marks the status line list, such as "on", "focus", "left-marker" or "right-marker". NoList resets it.
+	List   string
+	NoList bool
+
+	// Mouse range, such as "left", "window|1" or "user|name". NoRange ends it.
+	Range   string
+	NoRange bool
+
+	// Sets or restores the default style of the status line, "push-default" and "pop-default".
+	PushDefault bool
+	PopDefault  bool
+
+	// Elements not understood by the parser, kept so that they are serialized back.
+	Extra []string
+}
+
+// Creates an empty style.
+func NewStyle() *Style {
+	return &Style{}
+}
+
+// Parses a style. Elements are separated by commas or spaces.
+// Styles containing formats, such as "#{?pane_in_mode,fg=yellow,fg=green}", cannot be parsed.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#STYLES
+func ParseStyle(s string) (*Style, error) {
+	if strings.Contains(s, "#{") {
+		return nil, errors.New("cannot parse style containing formats")
+	}
+
+	style := NewStyle()
+	elements := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	for _, e := range elements {
+		err := style.parseElement(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return style, nil
+}
+
+// Parses a single style element.
+func (s *Style) parseElement(e string) error {
+	key, value, hasValue := strings.Cut(e, "=")
+	key = strings.ToLower(key)
+	if hasValue {
+		var err error
+		switch key {
+		case "fg":
+			s.Fg, err = ParseColour(value)
+		case "bg":
+			s.Bg, err = ParseColour(value)
+		case "us":
+			s.Us, err = ParseColour(value)
+		case "fill":
+			s.Fill, err = ParseColour(value)
+		case "align":
+			s.Align = value
+		case "list":
+			s.List = value
+		case "range":
+			s.Range = value
+		default:
+			s.Extra = append(s.Extra, e)
+		}
+		return err
+	}
+
+	switch key {
+	case "default":
+		s.Default = true
+	case "none":
+		s.None = true
+		s.Attrs = 0
+	case "noalign":
+		s.NoAlign = true
+	case "nolist":
+		s.NoList = true
+	case "norange":
+		s.NoRange = true
+	case "push-default":
+		s.PushDefault = true
+	case "pop-default":
+		s.PopDefault = true
+	default:
+		if a, ok := parseStyleAttr(key); ok {
+			s.Attrs |= a
+			s.NoAttrs &^= a
+		} else if a, ok := parseStyleAttr(strings.TrimPrefix(key, "no")); ok && strings.HasPrefix(key, "no") {
+			s.NoAttrs |= a
+			s.Attrs &^= a
+		} else {
+			s.Extra = append(s.Extra, e)
+		}
+	}
+
+	return nil
+}
+
+// Formats the style as accepted by tmux.
+func (s *Style) String() string {
+	elements := make([]string, 0)
+	if s.Default {
+		elements = append(elements, "default")
+	}
+
+	if s.None {
+		elements = append(elements, "none")
+	}
+
+	for _, c := range []struct {
+		key    string
+		colour Colour
+	}{{"fg", s.Fg}, {"bg", s.Bg}, {"us", s.Us}, {"fill", s.Fill}} {
+		if c.colour.IsSet() {
+			elements = append(elements, c.key+"="+c.colour.String())
+		}
+	}
+
+	elements = append(elements, s.Attrs.Names()...)
+	for _, n := range s.NoAttrs.Names() {
+		elements = append(elements, "no"+n)
+	}
+
+	if s.Align != "" {
+		elements = append(elements, "align="+s.Align)
+	}
+
+	if s.NoAlign {
+		elements = append(elements, "noalign")
+	}
+
+	if s.List != "" {
+		elements = append(elements, "list="+s.List)
+	}
+
+	if s.NoList {
+		elements = append(elements, "nolist")
+	}
+
+	if s.Range != "" {
+		elements = append(elements, "range="+s.Range)
+	}
+
+	if s.NoRange {
+		elements = append(elements, "norange")
+	}
+
+	if s.PushDefault {
+		elements = append(elements, "push-default")
+	}
+
+	if s.PopDefault {
+		elements = append(elements, "pop-default")
+	}
+
+	elements = append(elements, s.Extra...)
+	if len(elements) == 0 {
+		return "default"
+	}
+
+	return strings.Join(elements, ",")
+}
+
+// Implements encoding.TextMarshaler, so styles are written as strings in JSON.
+func (s *Style) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Implements encoding.TextUnmarshaler.
+func (s *Style) UnmarshalText(text []byte) error {
+	parsed, err := ParseStyle(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = *parsed
+	return nil
+}
+
+// Sets the foreground colour.
+func (s *Style) WithFg(c Colour) *Style {
+	s.Fg = c
+	return s
+}
+
+// Sets the background colour.
+func (s *Style) WithBg(c Colour) *Style {
+	s.Bg = c
+	return s
+}
+
+// Sets the underscore colour.
+func (s *Style) WithUs(c Colour) *Style {
+	s.Us = c
+	return s
+}
+
+// Sets the fill colour.
+func (s *Style) WithFill(c Colour) *Style {
+	s.Fill = c
+	return s
+}
+
+// Turns the attributes on.
+func (s *Style) WithAttrs(attrs StyleAttr) *Style {
+	s.Attrs |= attrs
+	s.NoAttrs &^= attrs
+	return s
+}
+
+// Turns the attributes off.
+func (s *Style) WithoutAttrs(attrs StyleAttr) *Style {
+	s.NoAttrs |= attrs
+	s.Attrs &^= attrs
+	return s
+}
+
+// Sets the alignment, one of the StyleAlign constants.
+func (s *Style) WithAlign(align string) *Style {
+	s.Align = align
+	return s
+}
+
+// Returns a copy of the style where the elements set in other override the elements of this style.
+func (s *Style) Merge(other *Style) *Style {
+	out := *s
+	out.Extra = append([]string{}, s.Extra...)
+
+	out.Default = out.Default || other.Default
+	if other.None {
+		out.None = true
+		out.Attrs = 0
+	}
+
+	for _, c := range []struct {
+		dst *Colour
+		src Colour
+	}{{&out.Fg, other.Fg}, {&out.Bg, other.Bg}, {&out.Us, other.Us}, {&out.Fill, other.Fill}} {
+		if c.src.IsSet() {
+			*c.dst = c.src
+		}
+	}
+
+	out.WithoutAttrs(other.NoAttrs)
+	out.WithAttrs(other.Attrs)
+
+	if other.Align != "" {
+		out.Align = other.Align
+	}
+
+	if other.List != "" {
+		out.List = other.List
+	}
+
+	if other.Range != "" {
+		out.Range = other.Range
+	}
+
+	out.NoAlign = out.NoAlign || other.NoAlign
+	out.NoList = out.NoList || other.NoList
+	out.NoRange = out.NoRange || other.NoRange
+	out.PushDefault = out.PushDefault || other.PushDefault
+	out.PopDefault = out.PopDefault || other.PopDefault
+	out.Extra = append(out.Extra, other.Extra...)
+	return &out
+}
+
+// Sets a style option at the target.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#STYLES
+func (t *Tmux) SetOptionStyle(target Target, key string, style *Style, level OptionScope) error {
+	return t.SetOption(target, key, style.String(), level)
+}
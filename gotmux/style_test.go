@@ -0,0 +1,152 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseColour(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Colour
+		str  string
+	}{
+		{"default", DefaultColour, "default"},
+		{"Terminal", TerminalColour, "terminal"},
+		{"red", NamedColour("red"), "red"},
+		{"BrightBlue", NamedColour("brightblue"), "brightblue"},
+		{"colour0", IndexedColour(0), "colour0"},
+		{"color255", IndexedColour(255), "colour255"},
+		{"#00ff7F", RGBColour(0, 255, 127), "#00ff7f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseColour(tt.s)
+			if err != nil {
+				t.Fatalf("ParseColour(%q) error: %v", tt.s, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColour(%q) = %+v, want %+v", tt.s, got, tt.want)
+			}
+			if got.String() != tt.str {
+				t.Errorf("ParseColour(%q).String() = %q, want %q", tt.s, got.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestParseColourInvalid(t *testing.T) {
+	for _, s := range []string{"", "colour256", "colour-1", "colourx", "#fff", "#gggggg", "#+12345"} {
+		if c, err := ParseColour(s); err == nil {
+			t.Errorf("ParseColour(%q) = %+v, want error", s, c)
+		}
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		s    string
+		want *Style
+		str  string
+	}{
+		{"", &Style{}, "default"},
+		{"default", &Style{Default: true}, "default"},
+		{"fg=red,bg=colour8", &Style{Fg: NamedColour("red"), Bg: IndexedColour(8)}, "fg=red,bg=colour8"},
+		{"bg=#000000 fg=red", &Style{Fg: NamedColour("red"), Bg: RGBColour(0, 0, 0)}, "fg=red,bg=#000000"},
+		{"us=blue,fill=default", &Style{Us: NamedColour("blue"), Fill: DefaultColour}, "us=blue,fill=default"},
+		{"italics,bright,BOLD", &Style{Attrs: StyleBold | StyleItalics}, "bold,italics"},
+		{"bold,nobold", &Style{NoAttrs: StyleBold}, "nobold"},
+		{"noreverse,reverse", &Style{Attrs: StyleReverse}, "reverse"},
+		{"bold,none,dim", &Style{None: true, Attrs: StyleDim}, "none,dim"},
+		{"curly-underscore,nodouble-underscore", &Style{Attrs: StyleCurlyUnderscore, NoAttrs: StyleDoubleUnderscore}, "curly-underscore,nodouble-underscore"},
+		{"align=centre,list=on,range=window|1", &Style{Align: StyleAlignCentre, List: "on", Range: "window|1"}, "align=centre,list=on,range=window|1"},
+		{"noalign,nolist,norange", &Style{NoAlign: true, NoList: true, NoRange: true}, "noalign,nolist,norange"},
+		{"push-default,pop-default", &Style{PushDefault: true, PopDefault: true}, "push-default,pop-default"},
+		{"fg=red,width=10,unknown", &Style{Fg: NamedColour("red"), Extra: []string{"width=10", "unknown"}}, "fg=red,width=10,unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseStyle(tt.s)
+			if err != nil {
+				t.Fatalf("ParseStyle(%q) error: %v", tt.s, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStyle(%q) = %+v, want %+v", tt.s, got, tt.want)
+			}
+			if got.String() != tt.str {
+				t.Errorf("ParseStyle(%q).String() = %q, want %q", tt.s, got.String(), tt.str)
+			}
+		})
+	}
+}
+
+// Serializing a parsed style and parsing it again gives the same style and string.
+func TestParseStyleRoundTrip(t *testing.T) {
+	styles := []string{
+		"default",
+		"fg=colour231,bg=#1e1e2e,bold",
+		"bg=red fg=brightwhite italics",
+		"none,underscore,noblink",
+		"bold,none,dim",
+		"fill=terminal,us=#ff0000,dotted-underscore",
+		"align=absolute-centre,list=left-marker,range=user|menu",
+		"push-default,norange,pop-default",
+		"fg=red,width=10,nohidden,unknown",
+	}
+
+	for _, s := range styles {
+		t.Run(s, func(t *testing.T) {
+			first, err := ParseStyle(s)
+			if err != nil {
+				t.Fatalf("ParseStyle(%q) error: %v", s, err)
+			}
+
+			str := first.String()
+			second, err := ParseStyle(str)
+			if err != nil {
+				t.Fatalf("ParseStyle(%q) error: %v", str, err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("ParseStyle(%q) = %+v, want %+v", str, second, first)
+			}
+			if second.String() != str {
+				t.Errorf("ParseStyle(%q).String() = %q, want %q", str, second.String(), str)
+			}
+		})
+	}
+}
+
+func TestParseStyleInvalid(t *testing.T) {
+	for _, s := range []string{"fg=colour999", "bg=#12", "#{?pane_in_mode,fg=yellow,fg=green}"} {
+		if st, err := ParseStyle(s); err == nil {
+			t.Errorf("ParseStyle(%q) = %+v, want error", s, st)
+		}
+	}
+}
+
+func TestStyleJSON(t *testing.T) {
+	want := NewStyle().WithFg(IndexedColour(4)).WithAttrs(StyleBold)
+
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"fg=colour4,bold"` {
+		t.Errorf("json.Marshal = %s, want %q", b, "fg=colour4,bold")
+	}
+
+	got := NewStyle()
+	if err := json.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Unmarshal = %+v, want %+v", got, want)
+	}
+}
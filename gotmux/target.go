@@ -0,0 +1,363 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Target of a tmux command, such as "=main", "$1", "@2", "%3" or "=main:1.0".
+// Taken by the methods of Tmux that act on a target. Plain names such as "main" also match sessions
+// starting with the name, the constructors and the Target methods of objects match exactly.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#COMMANDS
+type Target string
+
+// Returns the target as passed to tmux.
+func (t Target) String() string {
+	return string(t)
+}
+
+// Target of the session with exactly the given name.
+// Without the = prefix, tmux also matches sessions starting with the name.
+func SessionTarget(name string) Target {
+	return Target(exactSession(name))
+}
+
+// Target of the window at the index in the session with exactly the given name, or with the given id.
+func WindowTarget(session string, index int) Target {
+	return Target(exactSession(session)).Window(index)
+}
+
+// Target of the pane at the index in the window at the index in the session with exactly the given name, or with the given id.
+func PaneTarget(session string, window, pane int) Target {
+	return WindowTarget(session, window).Pane(pane)
+}
+
+// Target of a client, by name or tty. Used by commands taking a target client.
+func ClientTarget(name string) Target {
+	return Target(name)
+}
+
+// Special tokens usable as targets, or as the window or pane part of a target.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#COMMANDS
+const (
+	// The marked pane.
+	TargetMarked Target = "{marked}"
+
+	// The pane or window under the mouse, in key bindings.
+	TargetMouse Target = "{mouse}"
+
+	// The last (previously active) window or pane.
+	TargetLast Target = "{last}"
+
+	// The last window, short form of {last}.
+	TargetLastWindow Target = "!"
+
+	// The next and previous window, or pane by number.
+	TargetNext     Target = "{next}"
+	TargetPrevious Target = "{previous}"
+
+	// The lowest and highest numbered window.
+	TargetStart Target = "{start}"
+	TargetEnd   Target = "{end}"
+
+	// Panes by position in the window.
+	TargetTop         Target = "{top}"
+	TargetBottom      Target = "{bottom}"
+	TargetLeft        Target = "{left}"
+	TargetRight       Target = "{right}"
+	TargetTopLeft     Target = "{top-left}"
+	TargetTopRight    Target = "{top-right}"
+	TargetBottomLeft  Target = "{bottom-left}"
+	TargetBottomRight Target = "{bottom-right}"
+
+	// Panes relative to the active pane.
+	TargetUpOf    Target = "{up-of}"
+	TargetDownOf  Target = "{down-of}"
+	TargetLeftOf  Target = "{left-of}"
+	TargetRightOf Target = "{right-of}"
+)
+
+// Target of a window token, such as TargetNext, in the target session.
+func (t Target) WindowToken(token Target) Target {
+	return Target(string(t) + ":" + string(token))
+}
+
+// Target of a pane token, such as TargetBottom, in the target window.
+func (t Target) PaneToken(token Target) Target {
+	return Target(string(t) + "." + string(token))
+}
+
+// Target of the window at the index in the target session.
+func (t Target) Window(index int) Target {
+	return Target(string(t) + ":" + strconv.Itoa(index))
+}
+
+// Target of the pane at the index in the target window.
+func (t Target) Pane(index int) Target {
+	return Target(string(t) + "." + strconv.Itoa(index))
+}
+
+// Target of this session, by id.
+func (s *Session) Target() Target {
+	return Target(s.Id)
+}
+
+// Target of this window, by id.
+func (w *Window) Target() Target {
+	return Target(w.Id)
+}
+
+// Target of this pane, by id.
+func (p *Pane) Target() Target {
+	return Target(p.Id)
+}
+
+// Returns the target as passed to a -t flag.
+// A session matched exactly, such as "=main", is followed by a colon so that it also resolves as a window or pane target.
+func (t Target) arg() string {
+	s := string(t)
+	if strings.HasPrefix(s, "=") && !strings.Contains(s, ":") {
+		s += ":"
+	}
+
+	return s
+}
+
+// Makes a session name match exactly, leaving ids, exact names and full targets unchanged.
+func exactSession(name string) string {
+	if name == "" || strings.ContainsAny(name[:1], "$=@%{") || strings.ContainsAny(name, ":.") {
+		return name
+	}
+
+	return "=" + name
+}
+
+// Parts of a target, as written in the target string.
+// Empty parts are not given and default to the current session, window or pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#COMMANDS
+type TargetSpec struct {
+	// Session name, exact name (=name) or id ($id).
+	Session string
+
+	// Window index, name, id (@id) or token.
+	Window string
+
+	// Pane index, id (%id) or token.
+	Pane string
+}
+
+// Parses a target string, such as "main:1.0", "=main:{last}", "@2.1" or "%3", into its parts.
+// A target without a colon or a dot, such as "main", is taken as a session, and tokens and the
+// shorthands "!", "+" and "-" as windows.
+// The pane is separated at the first dot of the window part, as tmux does. Window names may also
+// contain dots, which cannot be told apart without a server, see Tmux.ParseTarget.
+func ParseTarget(s string) (*TargetSpec, error) {
+	if s == "" {
+		return nil, errors.New("empty target")
+	}
+
+	spec := &TargetSpec{}
+	switch {
+	case strings.HasPrefix(s, "%"):
+		spec.Pane = s
+		return spec, nil
+	case strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") && !strings.Contains(s, ":") && !strings.Contains(s, "."):
+		if Target(s) == TargetMarked || Target(s) == TargetMouse {
+			spec.Pane = s
+		} else {
+			spec.Window = s
+		}
+		return spec, nil
+	case isWindowShorthand(s):
+		spec.Window = s
+		return spec, nil
+	}
+
+	rest := s
+	if session, after, ok := strings.Cut(s, ":"); ok {
+		if strings.Contains(after, ":") {
+			return nil, errors.New("invalid target")
+		}
+		spec.Session = session
+		rest = after
+	} else if !strings.HasPrefix(s, "@") && !strings.Contains(s, ".") {
+		spec.Session = s
+		return spec, nil
+	}
+
+	// The first dot separates the pane, as in tmux.
+	if i := strings.IndexByte(rest, '.'); i != -1 {
+		spec.Window = rest[:i]
+		spec.Pane = rest[i+1:]
+	} else {
+		spec.Window = rest
+	}
+
+	return spec, nil
+}
+
+// Returns true for the window shorthands "!" (last window), and "+" and "-" (next and previous window) with an optional offset.
+func isWindowShorthand(s string) bool {
+	if s == string(TargetLastWindow) {
+		return true
+	}
+
+	offset, ok := strings.CutPrefix(s, "+")
+	if !ok {
+		offset, ok = strings.CutPrefix(s, "-")
+	}
+	if !ok {
+		return false
+	}
+
+	return strings.Trim(offset, "0123456789") == ""
+}
+
+// Formats the parts as a target.
+func (s *TargetSpec) Target() Target {
+	var b strings.Builder
+	b.WriteString(s.Session)
+	if s.Window != "" || s.Session != "" && s.Pane != "" {
+		if s.Session != "" || !strings.HasPrefix(s.Window, "@") {
+			b.WriteString(":")
+		}
+		b.WriteString(s.Window)
+	}
+
+	if s.Pane != "" {
+		// A pane alone is only a pane target if it is an id or a pane token, ".1" is needed for an index.
+		if b.Len() > 0 || !strings.HasPrefix(s.Pane, "%") && Target(s.Pane) != TargetMarked && Target(s.Pane) != TargetMouse {
+			b.WriteString(".")
+		}
+		b.WriteString(s.Pane)
+	}
+
+	return Target(b.String())
+}
+
+// Parses a target string into its parts, like ParseTarget, trying a window part containing a dot
+// as the whole name of a window of the session first. Such a window is given by id in the result,
+// since tmux splits the pane at the first dot.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#COMMANDS
+func (t *Tmux) ParseTarget(target Target) (*TargetSpec, error) {
+	spec, err := ParseTarget(string(target))
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Window == "" || spec.Pane == "" {
+		return spec, nil
+	}
+
+	q := t.query().
+		cmd("list-windows").
+		vars(varWindowId, varWindowName)
+
+	if spec.Session != "" {
+		q.fargs("-t", Target(spec.Session).arg())
+	}
+
+	o, err := q.run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	window := spec.Window + "." + spec.Pane
+	for _, w := range o.collect() {
+		if w.get(varWindowName) == window {
+			return &TargetSpec{Session: spec.Session, Window: w.get(varWindowId)}, nil
+		}
+	}
+
+	return spec, nil
+}
+
+// Concrete ids a target refers to.
+type ResolvedTarget struct {
+	SessionId string
+	WindowId  string
+	PaneId    string
+}
+
+// Resolves a target to the ids of its session, window and pane.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#display-message
+func (t *Tmux) ResolveTarget(target Target) (*ResolvedTarget, error) {
+	// Windows with a dot in their name are only found by id.
+	if spec, err := ParseTarget(string(target)); err == nil && spec.Window != "" && spec.Pane != "" {
+		spec, err := t.ParseTarget(target)
+		if err != nil {
+			return nil, errors.New("failed to resolve target")
+		}
+		target = spec.Target()
+	}
+
+	o, err := t.query().
+		cmd("display-message").
+		fargs("-t", target.arg()).
+		vars(varSessionId, varWindowId, varPaneId).
+		run()
+	if err != nil {
+		return nil, errors.New("failed to resolve target")
+	}
+
+	r := o.one()
+	resolved := &ResolvedTarget{
+		SessionId: r.get(varSessionId),
+		WindowId:  r.get(varWindowId),
+		PaneId:    r.get(varPaneId),
+	}
+
+	// Targets that do not exist expand to empty values.
+	if resolved.PaneId == "" {
+		return nil, errors.New("target not found")
+	}
+
+	return resolved, nil
+}
+
+// Gets the session a target refers to.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#COMMANDS
+func (t *Tmux) SessionAt(target Target) (*Session, error) {
+	r, err := t.ResolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.GetSessionById(r.SessionId)
+}
+
+// Gets the window a target refers to.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#COMMANDS
+func (t *Tmux) WindowAt(target Target) (*Window, error) {
+	r, err := t.ResolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.GetWindowById(r.WindowId)
+}
+
+// Gets the pane a target refers to.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#COMMANDS
+func (t *Tmux) PaneAt(target Target) (*Pane, error) {
+	r, err := t.ResolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.GetPaneById(r.PaneId)
+}
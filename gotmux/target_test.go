@@ -0,0 +1,122 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import "testing"
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		s      string
+		want   TargetSpec
+		target Target
+	}{
+		{"main", TargetSpec{Session: "main"}, "main"},
+		{"=main", TargetSpec{Session: "=main"}, "=main"},
+		{"$1", TargetSpec{Session: "$1"}, "$1"},
+		{"main:1", TargetSpec{Session: "main", Window: "1"}, "main:1"},
+		{"main:1.0", TargetSpec{Session: "main", Window: "1", Pane: "0"}, "main:1.0"},
+		{"=main:{last}", TargetSpec{Session: "=main", Window: "{last}"}, "=main:{last}"},
+		{"main:.2", TargetSpec{Session: "main", Pane: "2"}, "main:.2"},
+		{":1", TargetSpec{Window: "1"}, ":1"},
+		{":!", TargetSpec{Window: "!"}, ":!"},
+		{":.{top}", TargetSpec{Pane: "{top}"}, ".{top}"},
+		{".1", TargetSpec{Pane: "1"}, ".1"},
+		{"@2", TargetSpec{Window: "@2"}, "@2"},
+		{"@2.1", TargetSpec{Window: "@2", Pane: "1"}, "@2.1"},
+		{"$1:@2.{bottom}", TargetSpec{Session: "$1", Window: "@2", Pane: "{bottom}"}, "$1:@2.{bottom}"},
+		{"%3", TargetSpec{Pane: "%3"}, "%3"},
+		{"{marked}", TargetSpec{Pane: "{marked}"}, "{marked}"},
+		{"{mouse}", TargetSpec{Pane: "{mouse}"}, "{mouse}"},
+		{"{next}", TargetSpec{Window: "{next}"}, ":{next}"},
+		{"!", TargetSpec{Window: "!"}, ":!"},
+		{"+", TargetSpec{Window: "+"}, ":+"},
+		{"-", TargetSpec{Window: "-"}, ":-"},
+		{"+2", TargetSpec{Window: "+2"}, ":+2"},
+		{"-1", TargetSpec{Window: "-1"}, ":-1"},
+		{"!.1", TargetSpec{Window: "!", Pane: "1"}, ":!.1"},
+		{"-main", TargetSpec{Session: "-main"}, "-main"},
+		{"main:-", TargetSpec{Session: "main", Window: "-"}, "main:-"},
+		{"{end}.{last}", TargetSpec{Window: "{end}", Pane: "{last}"}, ":{end}.{last}"},
+
+		// The pane is separated at the first dot, as in tmux.
+		{"main:my.window", TargetSpec{Session: "main", Window: "my", Pane: "window"}, "main:my.window"},
+		{"main:a.b.c", TargetSpec{Session: "main", Window: "a", Pane: "b.c"}, "main:a.b.c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseTarget(tt.s)
+			if err != nil {
+				t.Fatalf("ParseTarget(%q) error: %v", tt.s, err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseTarget(%q) = %+v, want %+v", tt.s, *got, tt.want)
+			}
+			if got.Target() != tt.target {
+				t.Errorf("ParseTarget(%q).Target() = %q, want %q", tt.s, got.Target(), tt.target)
+			}
+
+			// The formatted target parses to the same parts.
+			again, err := ParseTarget(string(got.Target()))
+			if err != nil {
+				t.Fatalf("ParseTarget(%q) error: %v", got.Target(), err)
+			}
+			if *again != *got {
+				t.Errorf("ParseTarget(%q) = %+v, want %+v", got.Target(), *again, *got)
+			}
+		})
+	}
+}
+
+func TestParseTargetInvalid(t *testing.T) {
+	for _, s := range []string{"", "a:b:c"} {
+		if spec, err := ParseTarget(s); err == nil {
+			t.Errorf("ParseTarget(%q) = %+v, want error", s, *spec)
+		}
+	}
+}
+
+func TestTargetConstructors(t *testing.T) {
+	tests := []struct {
+		got  Target
+		want Target
+	}{
+		{SessionTarget("main"), "=main"},
+		{SessionTarget("$1"), "$1"},
+		{SessionTarget("=main"), "=main"},
+		{SessionTarget("main:1"), "main:1"},
+		{WindowTarget("main", 2), "=main:2"},
+		{WindowTarget("$1", 2), "$1:2"},
+		{PaneTarget("main", 2, 1), "=main:2.1"},
+		{SessionTarget("main").WindowToken(TargetLast), "=main:{last}"},
+		{WindowTarget("main", 2).PaneToken(TargetBottom), "=main:2.{bottom}"},
+		{Target("@4").Pane(0), "@4.0"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestTargetArg(t *testing.T) {
+	tests := []struct {
+		target Target
+		want   string
+	}{
+		{"=main", "=main:"},
+		{"=main:1", "=main:1"},
+		{"main", "main"},
+		{"$1", "$1"},
+		{"%3", "%3"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.target.arg(); got != tt.want {
+			t.Errorf("Target(%q).arg() = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// A set of options styling the status line, window list, pane borders, messages and modes.
+// Fields that are nil are left untouched when the theme is applied, empty values are set.
+// The JSON keys are the names of the options.
+// Styles are kept as option values, which may contain formats, and can be built with Style.String.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+type Theme struct {
+	Name string `json:"name,omitempty"`
+
+	// Status line.
+	StatusStyle       *string `json:"status-style,omitempty"`
+	StatusPosition    *string `json:"status-position,omitempty"`
+	StatusJustify     *string `json:"status-justify,omitempty"`
+	StatusLeft        *string `json:"status-left,omitempty"`
+	StatusLeftStyle   *string `json:"status-left-style,omitempty"`
+	StatusLeftLength  *int    `json:"status-left-length,omitempty"`
+	StatusRight       *string `json:"status-right,omitempty"`
+	StatusRightStyle  *string `json:"status-right-style,omitempty"`
+	StatusRightLength *int    `json:"status-right-length,omitempty"`
+
+	// Window list.
+	WindowStatusFormat        *string `json:"window-status-format,omitempty"`
+	WindowStatusStyle         *string `json:"window-status-style,omitempty"`
+	WindowStatusCurrentFormat *string `json:"window-status-current-format,omitempty"`
+	WindowStatusCurrentStyle  *string `json:"window-status-current-style,omitempty"`
+	WindowStatusLastStyle     *string `json:"window-status-last-style,omitempty"`
+	WindowStatusActivityStyle *string `json:"window-status-activity-style,omitempty"`
+	WindowStatusBellStyle     *string `json:"window-status-bell-style,omitempty"`
+	WindowStatusSeparator     *string `json:"window-status-separator,omitempty"`
+
+	// Pane borders.
+	PaneBorderStyle       *string `json:"pane-border-style,omitempty"`
+	PaneActiveBorderStyle *string `json:"pane-active-border-style,omitempty"`
+	PaneBorderLines       *string `json:"pane-border-lines,omitempty"`
+	PaneBorderStatus      *string `json:"pane-border-status,omitempty"`
+	PaneBorderFormat      *string `json:"pane-border-format,omitempty"`
+
+	// Messages, modes and indicators.
+	MessageStyle             *string `json:"message-style,omitempty"`
+	MessageCommandStyle      *string `json:"message-command-style,omitempty"`
+	ModeStyle                *string `json:"mode-style,omitempty"`
+	ClockModeColour          *Colour `json:"clock-mode-colour,omitempty"`
+	DisplayPanesColour       *Colour `json:"display-panes-colour,omitempty"`
+	DisplayPanesActiveColour *Colour `json:"display-panes-active-colour,omitempty"`
+
+	// Any other options, such as user options, keyed by name.
+	// Built-in options are set at the scope given by the option catalog, including server options,
+	// other options are session options.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// An option set by a theme.
+type themeOption struct {
+	key   string
+	value string
+
+	// One of OptionScopeServer, OptionScopeSession or OptionScopeWindow.
+	scope OptionScope
+}
+
+// A value of an option before a theme was applied.
+type themeSnapshotValue struct {
+	target Target
+	key    string
+	scope  OptionScope
+	value  string
+	set    bool
+}
+
+// Reads a theme from JSON.
+func ReadTheme(r io.Reader) (*Theme, error) {
+	theme := &Theme{}
+	err := json.NewDecoder(r).Decode(theme)
+	if err != nil {
+		return nil, errors.New("failed to decode theme")
+	}
+
+	return theme, nil
+}
+
+// Loads a theme from a JSON file.
+func LoadTheme(path string) (*Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New("failed to open theme")
+	}
+	defer f.Close()
+
+	return ReadTheme(f)
+}
+
+// Writes the theme as JSON.
+func (th *Theme) Write(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	err := e.Encode(th)
+	if err != nil {
+		return errors.New("failed to encode theme")
+	}
+
+	return nil
+}
+
+// Lists the options set by the theme.
+func (th *Theme) options() []themeOption {
+	out := make([]themeOption, 0)
+	add := func(key, value string) {
+		scope := OptionScopeSession
+		if schema, ok := LookupOptionSchema(key); ok {
+			scope = schema.Scope
+		}
+		out = append(out, themeOption{key: key, value: value, scope: scope})
+	}
+
+	str := func(key string, v *string) {
+		if v != nil {
+			add(key, *v)
+		}
+	}
+
+	colour := func(key string, c *Colour) {
+		if c != nil {
+			add(key, c.String())
+		}
+	}
+
+	number := func(key string, n *int) {
+		if n != nil {
+			add(key, strconv.Itoa(*n))
+		}
+	}
+
+	str("status-style", th.StatusStyle)
+	str("status-position", th.StatusPosition)
+	str("status-justify", th.StatusJustify)
+	str("status-left", th.StatusLeft)
+	str("status-left-style", th.StatusLeftStyle)
+	number("status-left-length", th.StatusLeftLength)
+	str("status-right", th.StatusRight)
+	str("status-right-style", th.StatusRightStyle)
+	number("status-right-length", th.StatusRightLength)
+
+	str("window-status-format", th.WindowStatusFormat)
+	str("window-status-style", th.WindowStatusStyle)
+	str("window-status-current-format", th.WindowStatusCurrentFormat)
+	str("window-status-current-style", th.WindowStatusCurrentStyle)
+	str("window-status-last-style", th.WindowStatusLastStyle)
+	str("window-status-activity-style", th.WindowStatusActivityStyle)
+	str("window-status-bell-style", th.WindowStatusBellStyle)
+	str("window-status-separator", th.WindowStatusSeparator)
+
+	str("pane-border-style", th.PaneBorderStyle)
+	str("pane-active-border-style", th.PaneActiveBorderStyle)
+	str("pane-border-lines", th.PaneBorderLines)
+	str("pane-border-status", th.PaneBorderStatus)
+	str("pane-border-format", th.PaneBorderFormat)
+
+	str("message-style", th.MessageStyle)
+	str("message-command-style", th.MessageCommandStyle)
+	str("mode-style", th.ModeStyle)
+	colour("clock-mode-colour", th.ClockModeColour)
+	colour("display-panes-colour", th.DisplayPanesColour)
+	colour("display-panes-active-colour", th.DisplayPanesActiveColour)
+
+	keys := make([]string, 0, len(th.Options))
+	for k := range th.Options {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		add(k, th.Options[k])
+	}
+
+	return out
+}
+
+// Applies the theme to the global options.
+// The previous values are kept until the theme is reverted with RevertTheme.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (t *Tmux) ApplyTheme(theme *Theme) error {
+	return t.applyTheme(theme, nil)
+}
+
+// Restores the global options changed by ApplyTheme.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (t *Tmux) RevertTheme() error {
+	return t.revertTheme("")
+}
+
+// Applies the theme to this session and its windows only.
+// Windows created after the theme is applied use the global window options.
+// Server options of the theme are not applied, since they cannot be set for a single session.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (s *Session) ApplyTheme(theme *Theme) error {
+	return s.tmux.applyTheme(theme, s)
+}
+
+// Restores the options of this session changed by ApplyTheme.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (s *Session) RevertTheme() error {
+	return s.tmux.revertTheme(s.Id)
+}
+
+// Applies a theme globally, or to a session if not nil, recording the previous values.
+func (t *Tmux) applyTheme(theme *Theme, session *Session) error {
+	// Targets of session and window options.
+	sessionTarget, sessionScope := Target(""), OptionScopeGlobalSession
+	windowTargets, windowScope := []Target{""}, OptionScopeGlobalWindow
+	key := ""
+	if session != nil {
+		sessionTarget, sessionScope = session.Target(), OptionScopeSession
+		windowScope = OptionScopeWindow
+		key = session.Id
+
+		windows, err := session.ListWindows()
+		if err != nil {
+			return errors.New("failed to apply theme")
+		}
+
+		windowTargets = make([]Target, 0, len(windows))
+		for _, w := range windows {
+			windowTargets = append(windowTargets, w.Target())
+		}
+	}
+
+	current := make(map[string]map[string]*Option)
+	lookup := func(target Target, scope OptionScope) (map[string]*Option, error) {
+		id := string(scope) + string(target)
+		if m, ok := current[id]; ok {
+			return m, nil
+		}
+
+		options, err := t.Options(target, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		m := make(map[string]*Option, len(options))
+		for _, o := range options {
+			if !o.Array {
+				m[o.Key] = o
+			}
+		}
+		current[id] = m
+		return m, nil
+	}
+
+	t.themesMu.Lock()
+	defer t.themesMu.Unlock()
+	if t.themes == nil {
+		t.themes = make(map[string][]themeSnapshotValue)
+	}
+
+	for _, o := range theme.options() {
+		targets, scope := []Target{sessionTarget}, sessionScope
+		switch o.scope {
+		case OptionScopeServer:
+			if session != nil {
+				continue
+			}
+			targets, scope = []Target{""}, OptionScopeServer
+		case OptionScopeWindow:
+			targets, scope = windowTargets, windowScope
+		}
+
+		for _, target := range targets {
+			options, err := lookup(target, scope)
+			if err != nil {
+				return errors.New("failed to apply theme")
+			}
+
+			// Only the value before the first theme is kept, so reverting restores the original options.
+			recorded := slices.ContainsFunc(t.themes[key], func(v themeSnapshotValue) bool {
+				return v.target == target && v.key == o.key && v.scope == scope
+			})
+			if !recorded {
+				prev, set := options[o.key]
+				v := themeSnapshotValue{target: target, key: o.key, scope: scope, set: set}
+				if set {
+					v.value = prev.Value
+				}
+				t.themes[key] = append(t.themes[key], v)
+			}
+
+			err = t.SetOption(target, o.key, o.value, scope)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Restores the values recorded when a theme was applied globally or to a session.
+func (t *Tmux) revertTheme(key string) error {
+	t.themesMu.Lock()
+	defer t.themesMu.Unlock()
+
+	snapshot, ok := t.themes[key]
+	if !ok {
+		return errors.New("no theme applied")
+	}
+
+	var failed bool
+	for i := len(snapshot) - 1; i >= 0; i-- {
+		v := snapshot[i]
+		var err error
+		if v.set {
+			err = t.SetOption(v.target, v.key, v.value, v.scope)
+		} else {
+			err = t.DeleteOption(v.target, v.key, v.scope)
+		}
+
+		if err != nil {
+			failed = true
+		}
+	}
+
+	delete(t.themes, key)
+	if failed {
+		return errors.New("failed to revert theme")
+	}
+
+	return nil
+}
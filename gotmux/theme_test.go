@@ -0,0 +1,35 @@
+// Copyright (c) Gianluca Piccirillo
+// This software is licensed under the MIT License.
+// See the LICENSE file in the root directory for more information.
+
+package gotmux
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestThemeOptions(t *testing.T) {
+	th, err := ReadTheme(strings.NewReader(`{
+		"status-left": "",
+		"status-left-length": 0,
+		"status-style": "fg=red",
+		"options": {"window-status-separator": "", "escape-time": "10", "@user": "x"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []themeOption{
+		{key: "status-style", value: "fg=red", scope: OptionScopeSession},
+		{key: "status-left", value: "", scope: OptionScopeSession},
+		{key: "status-left-length", value: "0", scope: OptionScopeSession},
+		{key: "@user", value: "x", scope: OptionScopeSession},
+		{key: "escape-time", value: "10", scope: OptionScopeServer},
+		{key: "window-status-separator", value: "", scope: OptionScopeWindow},
+	}
+	if got := th.options(); !reflect.DeepEqual(got, want) {
+		t.Errorf("options() = %+v, want %+v", got, want)
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Entrypoint object to the library.
@@ -17,6 +20,13 @@ import (
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#DESCRIPTION
 type Tmux struct {
 	Socket *Socket
+
+	events   *eventListener
+	eventsMu sync.Mutex
+
+	// Option values recorded before themes were applied, keyed by session id or empty for global themes.
+	themes   map[string][]themeSnapshotValue
+	themesMu sync.Mutex
 }
 
 // Initializes the tmux client with a socket path.
@@ -58,7 +68,11 @@ func (t *Tmux) GetServerInformation() (*Server, error) {
 		return nil, err
 	}
 
-	server := o.one().toServer(t)
+	server, err := o.one().toServer(t)
+	if err != nil {
+		return nil, err
+	}
+
 	return server, nil
 }
 
@@ -77,7 +91,10 @@ func (t *Tmux) ListClients() ([]*Client, error) {
 	result := output.collect()
 	out := make([]*Client, 0)
 	for _, item := range result {
-		c := item.toClient(t)
+		c, err := item.toClient(t)
+		if err != nil {
+			return nil, err
+		}
 		out = append(out, c)
 	}
 
@@ -99,7 +116,10 @@ func (t *Tmux) ListSessions() ([]*Session, error) {
 	result := output.collect()
 	out := make([]*Session, 0)
 	for _, item := range result {
-		s := item.toSession(t)
+		s, err := item.toSession(t)
+		if err != nil {
+			return nil, err
+		}
 		out = append(out, s)
 	}
 
@@ -107,12 +127,13 @@ func (t *Tmux) ListSessions() ([]*Session, error) {
 }
 
 // Returns true if the session exists, false otherwise.
+// Names are matched exactly, ids such as "$1" are also accepted.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#has-session
 func (t *Tmux) HasSession(session string) bool {
 	_, err := t.query().
 		cmd("has-session").
-		fargs("-t", session).
+		fargs("-t", exactSession(session)).
 		run()
 
 	return err == nil
@@ -136,6 +157,24 @@ func (t *Tmux) GetSessionByName(name string) (*Session, error) {
 	return nil, nil
 }
 
+// Gets a session by id, such as "$1".
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#session_id
+func (t *Tmux) GetSessionById(id string) (*Session, error) {
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil, errors.New("failed to get session by id")
+	}
+
+	for _, s := range sessions {
+		if s.Id == id {
+			return s, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // Gets a session by name. Shorthand for `GetSessionByName`.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#session_name
@@ -161,15 +200,58 @@ func (t *Tmux) GetClientByTty(tty string) (*Client, error) {
 	return nil, nil
 }
 
+// Gets a client by name.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#client_name
+func (t *Tmux) GetClientByName(name string) (*Client, error) {
+	clients, err := t.ListClients()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range clients {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // Options object for creating a session.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#new-session
 type SessionOptions struct {
-	Name           string
-	ShellCommand   string
+	Name string
+
+	// Command run by the shell in the first window, passed as is.
+	ShellCommand string
+
 	StartDirectory string
 	Width          int
 	Height         int
+
+	// Name of the first window.
+	WindowName string
+
+	// Environment variables set in the session.
+	Environment map[string]string
+
+	// Name or id of an existing session to group the new session with.
+	// Grouped sessions share the same set of windows.
+	GroupWith string
+
+	// Returns the existing session with the same name instead of failing.
+	AttachIfExists bool
+
+	// Detaches the other clients from the existing session, with AttachIfExists.
+	DetachOthers bool
+
+	// Sends SIGHUP to the parent process of the detached clients, with DetachOthers.
+	HangupOthers bool
+
+	// Does not apply the update-environment option.
+	NoUpdateEnvironment bool
 }
 
 // Creates a new session without attaching to it.
@@ -205,21 +287,85 @@ func (t *Tmux) NewSession(op *SessionOptions) (*Session, error) {
 			q.fargs("-y", h)
 		}
 
+		if op.WindowName != "" {
+			q.fargs("-n", op.WindowName)
+		}
+
+		for _, e := range environmentArgs(op.Environment) {
+			q.fargs("-e", e)
+		}
+
+		if op.GroupWith != "" {
+			q.fargs("-t", exactSession(op.GroupWith))
+		}
+
+		if op.NoUpdateEnvironment {
+			q.fargs("-E")
+		}
+
 		if op.ShellCommand != "" {
-			s := fmt.Sprintf("'%s'", op.ShellCommand)
-			q.pargs(s)
+			q.pargs(op.ShellCommand)
 		}
 	}
 
+	// new-session -A attaches the calling client, which has no terminal and fails.
+	// Creating the session first and falling back to the existing one is atomic on the server.
 	o, err := q.run()
 	if err != nil {
+		if op != nil && op.AttachIfExists && op.Name != "" && t.HasSession(op.Name) {
+			return t.existingSession(op)
+		}
 		return nil, errors.New("failed to create session")
 	}
 
-	s := o.one().toSession(t)
+	return o.one().toSession(t)
+}
+
+// Returns the existing session for NewSession with AttachIfExists, detaching its other clients if requested.
+func (t *Tmux) existingSession(op *SessionOptions) (*Session, error) {
+	s, err := t.GetSessionByName(op.Name)
+	if err != nil || s == nil {
+		return nil, errors.New("failed to get existing session")
+	}
+
+	// detach-client fails on a session without clients.
+	if op.DetachOthers && s.Attached > 0 {
+		q := t.query().
+			cmd("detach-client").
+			fargs("-s", s.Id)
+
+		if op.HangupOthers {
+			q.fargs("-P")
+		}
+
+		_, err := q.run()
+		if err != nil {
+			return nil, errors.New("failed to detach clients")
+		}
+
+		s.Attached = 0
+		s.AttachedList = []string{}
+	}
+
 	return s, nil
 }
 
+// Formats environment variables as NAME=value, sorted by name.
+func environmentArgs(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for k := range env {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	out := make([]string, 0, len(names))
+	for _, k := range names {
+		out = append(out, k+"="+env[k])
+	}
+
+	return out
+}
+
 // Creates a new session without attaching.
 // Shorthand for 'NewSession', but with default options.
 //
@@ -232,8 +378,8 @@ func (w *Tmux) New() (*Session, error) {
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#detach-client
 type DetachClientOptions struct {
-	TargetClient  string
-	TargetSession string
+	TargetClient  Target
+	TargetSession Target
 }
 
 // Detaches current client, a target client or all the clients of a target session.
@@ -245,9 +391,9 @@ func (t *Tmux) DetachClient(op *DetachClientOptions) error {
 
 	if op != nil {
 		if op.TargetClient != "" {
-			q.fargs("-t", op.TargetClient)
+			q.fargs("-t", string(op.TargetClient))
 		} else if op.TargetSession != "" {
-			q.fargs("-s", op.TargetSession)
+			q.fargs("-s", exactSession(string(op.TargetSession)))
 		}
 	}
 
@@ -261,8 +407,8 @@ func (t *Tmux) DetachClient(op *DetachClientOptions) error {
 
 // Switch Client command options
 type SwitchClientOptions struct {
-	TargetSession string
-	TargetClient  string
+	TargetSession Target
+	TargetClient  Target
 }
 
 // Switches client to TargetSession for a TargetClient.
@@ -274,10 +420,10 @@ func (t *Tmux) SwitchClient(op *SwitchClientOptions) error {
 
 	if op != nil {
 		if op.TargetClient != "" {
-			q.fargs("-c", op.TargetClient)
+			q.fargs("-c", string(op.TargetClient))
 		}
 		if op.TargetSession != "" {
-			q.fargs("-t", op.TargetSession)
+			q.fargs("-t", exactSession(string(op.TargetSession)))
 		}
 	}
 
@@ -316,7 +462,10 @@ func (t *Tmux) ListAllWindows() ([]*Window, error) {
 
 	out := make([]*Window, 0)
 	for _, res := range o.collect() {
-		w := res.toWindow(t)
+		w, err := res.toWindow(t)
+		if err != nil {
+			return nil, err
+		}
 		out = append(out, w)
 	}
 
@@ -338,7 +487,10 @@ func (t *Tmux) ListAllPanes() ([]*Pane, error) {
 
 	out := make([]*Pane, 0)
 	for _, r := range o.collect() {
-		p := r.toPane(t)
+		p, err := r.toPane(t)
+		if err != nil {
+			return nil, err
+		}
 		out = append(out, p)
 	}
 
@@ -393,7 +545,10 @@ func (t *Tmux) GetClient() (*Client, error) {
 		return nil, err
 	}
 
-	client := o.one().toClient(t)
+	client, err := o.one().toClient(t)
+	if err != nil {
+		return nil, err
+	}
 	if client.Height == 0 {
 		return nil, nil
 	}
@@ -402,19 +557,89 @@ func (t *Tmux) GetClient() (*Client, error) {
 }
 
 // Sets an option at the target with given key.
-// level can be one of:
+// The target is ignored for global scopes if empty.
 //
-//	-p (pane), -w (window), -s (server) or empty (session)
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
+func (t *Tmux) SetOption(target Target, key, option string, level OptionScope) error {
+	return t.SetOptionWith(target, key, option, level, nil)
+}
+
+// Set option command options.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
+type SetOptionOptions struct {
+	// Appends the value to the existing value, or adds an element to an array option.
+	Append bool
+
+	// Only sets the option if it is not already set.
+	OnlyIfUnset bool
+
+	// Does not fail if the option is unknown or ambiguous.
+	Quiet bool
+
+	// Expands the formats in the value before setting it.
+	Format bool
+
+	// Unsets the option. The value is ignored.
+	Unset bool
+
+	// Unsets the option, and for pane options also unsets it on all the panes of the window.
+	// The value is ignored.
+	UnsetPanes bool
+
+	// Validates the option and value with ValidateOption before setting it.
+	Validate bool
+}
+
+// Sets an option at the target with given key and options.
+// The target is ignored for global scopes if empty.
+// Values are only validated against the option catalog if requested in the options.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
-func (t *Tmux) SetOption(target, key, option, level string) error {
+func (t *Tmux) SetOptionWith(target Target, key, option string, level OptionScope, op *SetOptionOptions) error {
 	q := t.query().
 		cmd("set-option")
-	if level != "" {
-		q.fargs(level)
+	level.args(q, target)
+
+	unset := false
+	if op != nil {
+		if op.Append {
+			q.fargs("-a")
+		}
+
+		if op.OnlyIfUnset {
+			q.fargs("-o")
+		}
+
+		if op.Quiet {
+			q.fargs("-q")
+		}
+
+		if op.Format {
+			q.fargs("-F")
+		}
+
+		if op.Unset {
+			q.fargs("-u")
+			unset = true
+		}
+
+		if op.UnsetPanes {
+			q.fargs("-U")
+			unset = true
+		}
+	}
+
+	if op != nil && op.Validate && !unset {
+		if err := t.ValidateOption(key, option, level); err != nil {
+			return err
+		}
+	}
+
+	q.pargs(key)
+	if !unset {
+		q.pargs(option)
 	}
-	q.fargs("-t", target).
-		pargs(key, option)
 
 	_, err := q.run()
 	if err != nil {
@@ -427,34 +652,39 @@ func (t *Tmux) SetOption(target, key, option, level string) error {
 // Retrieves an option.
 //
 // https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
-func (t *Tmux) Option(target, key, level string) (*Option, error) {
-	q := t.query().
-		cmd("show-option")
-	if level != "" {
-		q.fargs(level)
+func (t *Tmux) Option(target Target, key string, level OptionScope) (*Option, error) {
+	v, err := t.optionValue(target, key, level, false)
+	if err != nil {
+		return nil, err
 	}
-	q.fargs("-t", target).
-		fargs("-v", key)
+
+	return newOption(key, v), nil
+}
+
+// Retrieves all options with provided params.
+//
+// https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
+func (t *Tmux) Options(target Target, level OptionScope) ([]*Option, error) {
+	q := t.query().cmd("show-options")
+	level.args(q, target)
 
 	o, err := q.run()
 	if err != nil {
-		return nil, errors.New("failed to retrieve option")
+		return nil, errors.New("failed to retrieve options")
 	}
 
-	return newOption(key, o.raw()), nil
+	return o.toOptions(), nil
 }
 
-// Retrieves all options with provided params.
+// Retrieves all options with provided params, including the options inherited from the parent scope.
+// Inherited options are marked as such.
 //
 // https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
-func (t *Tmux) Options(target, level string) ([]*Option, error) {
+func (t *Tmux) EffectiveOptions(target Target, level OptionScope) ([]*Option, error) {
 	q := t.query().cmd("show-options")
-	if level != "" {
-		q.fargs(level)
-	}
-	q.fargs("-t", target)
+	level.args(q, target)
 
-	o, err := q.run()
+	o, err := q.fargs("-A").run()
 	if err != nil {
 		return nil, errors.New("failed to retrieve options")
 	}
@@ -465,21 +695,36 @@ func (t *Tmux) Options(target, level string) ([]*Option, error) {
 // Deletes an option from this session.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
-func (t *Tmux) DeleteOption(target, key, level string) error {
+func (t *Tmux) DeleteOption(target Target, key string, level OptionScope) error {
+	err := t.SetOptionWith(target, key, "", level, &SetOptionOptions{Unset: true})
+	if err != nil {
+		return errors.New("failed to delete option")
+	}
+
+	return nil
+}
+
+// Expands a format expression on the server, in the context of the target.
+// The target may be empty for the current pane.
+// Expressions can be built with the format package.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#FORMATS
+func (t *Tmux) DisplayFormat(target Target, expr string) (string, error) {
 	q := t.query().
-		cmd("set-option")
-	if level != "" {
-		q.fargs(level)
+		cmd("display-message").
+		fargs("-p")
+
+	if target != "" {
+		q.fargs("-t", target.arg())
 	}
-	q.fargs("-t", target).
-		fargs("-u", key)
 
-	_, err := q.run()
+	// Expressions starting with - would be taken as flags.
+	o, err := q.pargs("--", expr).run()
 	if err != nil {
-		return errors.New("failed to delete option")
+		return "", errors.New("failed to display format")
 	}
 
-	return nil
+	return strings.TrimSuffix(o.raw(), "\n"), nil
 }
 
 // Runs a tmux command. For custom commands that the API does not cover.
@@ -513,6 +758,20 @@ func parseList(l string) []string {
 	return strings.Split(l, ",")
 }
 
+// Parses a unix timestamp. Empty values, for events that did not happen, are the zero time.
+func parseTime(v string) (time.Time, error) {
+	if v == "" || v == "0" {
+		return time.Time{}, nil
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", v)
+	}
+
+	return time.Unix(n, 0), nil
+}
+
 // Checks the validity of the tmux session name.
 func checkSessionName(name string) bool {
 	if len(name) == 0 {
@@ -8,6 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
+
+	"github.com/GianlucaP106/gotmux/gotmux/screen"
 )
 
 // Tmux window object.
@@ -19,7 +22,7 @@ type Window struct {
 	ActiveClientsList  []string
 	ActiveSessions     int
 	ActiveSessionsList []string
-	Activity           string
+	Activity           time.Time
 	ActivityFlag       bool
 	BellFlag           bool
 	Bigger             bool
@@ -82,13 +85,48 @@ func (w *Window) ListPanes() ([]*Pane, error) {
 
 	out := make([]*Pane, 0)
 	for _, item := range o.collect() {
-		pane := item.toPane(w.tmux)
+		pane, err := item.toPane(w.tmux)
+		if err != nil {
+			return nil, err
+		}
 		out = append(out, pane)
 	}
 
 	return out, nil
 }
 
+// Re-reads the fields of the window from tmux, by window id.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#display-message
+func (w *Window) Refresh() error {
+	o, err := w.tmux.query().
+		cmd("display-message").
+		fargs("-t", w.Id).
+		windowVars().
+		run()
+	if err != nil {
+		return errors.New("failed to refresh window")
+	}
+
+	fresh, err := o.one().toWindow(w.tmux)
+	if err != nil {
+		return err
+	}
+
+	// Targets that do not exist are not an error for display-message, but expand to empty values.
+	if fresh.Id != w.Id {
+		return errors.New("window not found")
+	}
+
+	*w = *fresh
+	return nil
+}
+
+// Returns the time since the last activity in the window.
+func (w *Window) IdleFor() time.Duration {
+	return time.Since(w.Activity)
+}
+
 // Kills the window.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#kill-window
@@ -117,6 +155,7 @@ func (w *Window) Rename(newName string) error {
 		return errors.New("failed to rename window")
 	}
 
+	w.Name = newName
 	return nil
 }
 
@@ -132,7 +171,7 @@ func (w *Window) Select() error {
 		return errors.New("failed to select window")
 	}
 
-	return nil
+	return w.Refresh()
 }
 
 // Selects the layout for this window.
@@ -148,24 +187,24 @@ func (w *Window) SelectLayout(layout WindowLayout) error {
 		return errors.New("failed to select layout")
 	}
 
-	return nil
+	return w.Refresh()
 }
 
 // Move this window to a different location.
 // This will return an error if the window already exists.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#move-window
-func (w *Window) Move(targetSession string, targetIdx int) error {
+func (w *Window) Move(targetSession Target, targetIdx int) error {
 	_, err := w.tmux.query().
 		cmd("move-window").
 		fargs("-s", w.Id).
-		fargs("-t", fmt.Sprintf("%s:%d", targetSession, targetIdx)).
+		fargs("-t", fmt.Sprintf("%s:%d", exactSession(string(targetSession)), targetIdx)).
 		run()
 	if err != nil {
 		return errors.New("failed to move window")
 	}
 
-	return nil
+	return w.Refresh()
 }
 
 // Gets a pane by index in this window.
@@ -235,33 +274,73 @@ func (w *Window) ListActiveClients() ([]*Client, error) {
 	return out, nil
 }
 
+// Captures the visible content of the window as a screen of cells.
+// The panes are composed using their position and size, separated by borders.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#capture-pane
+func (w *Window) Screen() (*screen.Screen, error) {
+	panes, err := w.ListPanes()
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := w.Width, w.Height
+	regions := make([]screen.Region, 0, len(panes))
+	for _, p := range panes {
+		s, err := p.Screen()
+		if err != nil {
+			return nil, err
+		}
+
+		left, _ := strconv.Atoi(p.Left)
+		top, _ := strconv.Atoi(p.Top)
+		width = max(width, left+s.Width)
+		height = max(height, top+s.Height)
+		regions = append(regions, screen.Region{
+			X:      left,
+			Y:      top,
+			Screen: s,
+			Active: p.Active,
+		})
+	}
+
+	return screen.Compose(width, height, regions), nil
+}
+
 // Sets an option with a given key.
 // Note that custom options must begin with '@'.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
 func (w *Window) SetOption(key, option string) error {
-	return w.tmux.SetOption(w.Id, key, option, "-w")
+	return w.tmux.SetOption(w.Target(), key, option, OptionScopeWindow)
 }
 
 // Retrieves an option from this window.
 //
 // https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
 func (w *Window) Option(key string) (*Option, error) {
-	return w.tmux.Option(w.Id, key, "-w")
+	return w.tmux.Option(w.Target(), key, OptionScopeWindow)
 }
 
 // Retrieves all options in this window.
 //
 // https://man.openbsd.org/OpenBSD-current/man1/tmux.1#show-options
 func (w *Window) Options() ([]*Option, error) {
-	return w.tmux.Options(w.Id, "-w")
+	return w.tmux.Options(w.Target(), OptionScopeWindow)
+}
+
+// Lists the schemas of the built-in options that can be set on this window.
+//
+// Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#OPTIONS
+func (w *Window) OptionSchemas() ([]*OptionSchema, error) {
+	return w.tmux.OptionSchemas(OptionScopeWindow)
 }
 
 // Deletes an option from this window.
 //
 // Reference: https://man.openbsd.org/OpenBSD-current/man1/tmux.1#set-option
 func (w *Window) DeleteOption(key string) error {
-	return w.tmux.DeleteOption(w.Id, key, "-w")
+	return w.tmux.DeleteOption(w.Target(), key, OptionScopeWindow)
 }
 
 // Sets the window variables in the query.
@@ -305,13 +384,16 @@ func (q *query) windowVars() *query {
 }
 
 // Converts a QueryResult to a Window.
-func (q queryResult) toWindow(t *Tmux) *Window {
+func (q queryResult) toWindow(t *Tmux) (*Window, error) {
 	active := isOne(q.get(varWindowActive))
 	activeClients, _ := strconv.Atoi(q.get(varWindowActiveClients))
 	activeClientsList := parseList(q.get(varWindowActiveClientsList))
 	activeSessions, _ := strconv.Atoi(q.get(varWindowActiveSessions))
 	activeSessionsList := parseList(q.get(varWindowActiveSessionsList))
-	activity := q.get(varWindowActivity)
+	activity, err := parseTime(q.get(varWindowActivity))
+	if err != nil {
+		return nil, err
+	}
 	activityFlag := isOne(q.get(varWindowActivityFlag))
 	bellFlag := isOne(q.get(varWindowBellFlag))
 	bigger := isOne(q.get(varWindowBigger))
@@ -380,5 +462,5 @@ func (q queryResult) toWindow(t *Tmux) *Window {
 		tmux: t,
 	}
 
-	return w
+	return w, nil
 }